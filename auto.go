@@ -0,0 +1,339 @@
+package restclientgo
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type autoFieldKind string
+
+const (
+	autoPath   autoFieldKind = "path"
+	autoQuery  autoFieldKind = "query"
+	autoHeader autoFieldKind = "header"
+	autoBody   autoFieldKind = "body"
+)
+
+// autoField is a struct field mapped to a part of the request by its rest tag.
+type autoField struct {
+	index []int
+	name  string
+	kind  autoFieldKind
+	query queryField
+}
+
+// AutoRequest is a Request built from the rest tags of a struct:
+//
+//	type GetUserPostsRequest struct {
+//		UserID string `rest:"path=id"`
+//		Limit  int    `rest:"query=limit,omitempty"`
+//		Tenant string `rest:"header=X-Tenant"`
+//		Filter Filter `rest:"body,contentType=application/json"`
+//	}
+//
+// Path params fill the placeholders of the path template. Query params accept the
+// omitempty, style and explode options of the url tag of EncodeQuery, and like it
+// format time.Time fields with the layout tag. Header fields are omitted when nil,
+// or zero with omitempty, and slices add one header value per element. The body is
+// encoded with the Codec registered for its content type, application/json by default.
+type AutoRequest struct {
+	path        PathTemplate
+	value       reflect.Value
+	fields      []autoField
+	contentType string
+	codec       Codec
+}
+
+// Auto creates an AutoRequest from v, a struct or a pointer to a struct. Fields are read
+// when the request is sent, so a pointer reflects later changes. The tags are validated
+// against the path template and the field types, returning an error wrapping
+// ErrAutoRequest for the first invalid field.
+func Auto(path PathTemplate, v any) (*AutoRequest, error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T is not a struct or a pointer to a struct", ErrAutoRequest, v)
+	}
+
+	autoRequest := &AutoRequest{path: path, value: value}
+	if err := autoRequest.parseFields(value.Type(), nil); err != nil {
+		return nil, err
+	}
+
+	if err := autoRequest.validatePath(); err != nil {
+		return nil, err
+	}
+
+	return autoRequest, nil
+}
+
+func (a *AutoRequest) parseFields(structType reflect.Type, index []int) error {
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		fieldIndex := append(slices.Clone(index), i)
+
+		tag, hasTag := field.Tag.Lookup("rest")
+		if !hasTag {
+			if field.Anonymous && isEmbeddedStruct(field.Type) {
+				embeddedType := field.Type
+				if embeddedType.Kind() == reflect.Pointer {
+					embeddedType = embeddedType.Elem()
+				}
+				if err := a.parseFields(embeddedType, fieldIndex); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			return fmt.Errorf("%w: field %s: rest tag on unexported field", ErrAutoRequest, field.Name)
+		}
+
+		autoField, err := a.parseField(field, tag)
+		if err != nil {
+			return fmt.Errorf("%w: field %s: %w", ErrAutoRequest, field.Name, err)
+		}
+		autoField.index = fieldIndex
+
+		a.fields = append(a.fields, autoField)
+	}
+
+	return nil
+}
+
+func (a *AutoRequest) parseField(field reflect.StructField, tag string) (autoField, error) {
+	kindAndName, options, _ := strings.Cut(tag, ",")
+	kind, name, _ := strings.Cut(kindAndName, "=")
+
+	autoField := autoField{name: name, kind: autoFieldKind(kind)}
+	switch autoField.kind {
+	case autoPath, autoQuery, autoHeader:
+		if name == "" {
+			return autoField, fmt.Errorf("missing %s name in %q", kind, tag)
+		}
+	case autoBody:
+		if name != "" {
+			return autoField, fmt.Errorf("unexpected body name in %q", tag)
+		}
+	default:
+		return autoField, fmt.Errorf("unknown rest tag kind %q", kind)
+	}
+
+	for _, existing := range a.fields {
+		if existing.kind == autoField.kind && strings.EqualFold(existing.name, autoField.name) {
+			if autoField.kind == autoBody {
+				return autoField, fmt.Errorf("duplicate body field")
+			}
+			return autoField, fmt.Errorf("duplicate %s %s", kind, name)
+		}
+	}
+
+	query, err := parseQueryOptions(name, options, field.Tag.Get("layout"))
+	if err != nil {
+		return autoField, err
+	}
+	autoField.query = query
+
+	switch autoField.kind {
+	case autoPath:
+		if !isAutoScalar(field.Type) {
+			return autoField, fmt.Errorf("unsupported path param type %s", field.Type)
+		}
+	case autoQuery:
+		if !isAutoScalar(field.Type) && !isAutoScalarSlice(field.Type) && !isAutoObject(field.Type) {
+			return autoField, fmt.Errorf("unsupported query param type %s", field.Type)
+		}
+	case autoHeader:
+		if !isAutoScalar(field.Type) && !isAutoScalarSlice(field.Type) {
+			return autoField, fmt.Errorf("unsupported header type %s", field.Type)
+		}
+	case autoBody:
+		a.contentType = "application/json"
+		for _, option := range strings.Split(options, ",") {
+			if contentType, found := strings.CutPrefix(option, "contentType="); found {
+				a.contentType = contentType
+			}
+		}
+
+		codec, ok := CodecFor(a.contentType)
+		if !ok {
+			return autoField, fmt.Errorf("no codec registered for content type %s", a.contentType)
+		}
+		a.codec = codec
+	}
+
+	return autoField, nil
+}
+
+// validatePath checks that path params and template placeholders match.
+func (a *AutoRequest) validatePath() error {
+	placeholders, err := a.path.placeholders()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrAutoRequest, err)
+	}
+
+	for _, placeholder := range placeholders {
+		if !slices.ContainsFunc(a.fields, func(field autoField) bool {
+			return field.kind == autoPath && field.name == placeholder
+		}) {
+			return fmt.Errorf("%w: no path field for {%s} in %s", ErrAutoRequest, placeholder, a.path)
+		}
+	}
+
+	for _, field := range a.fields {
+		if field.kind == autoPath && !slices.Contains(placeholders, field.name) {
+			return fmt.Errorf("%w: path param %s not in %s", ErrAutoRequest, field.name, a.path)
+		}
+	}
+
+	return nil
+}
+
+// Path expands the path template with the path params and appends the query params.
+func (a *AutoRequest) Path() (string, error) {
+	params := make(PathParams)
+	query := NewURLValues()
+
+	for _, field := range a.fields {
+		value, ok := a.fieldValue(field)
+		if !ok {
+			continue
+		}
+
+		switch field.kind {
+		case autoPath:
+			formatted, formattedOK, err := formatQueryValue(value, field.query.layout)
+			if err != nil {
+				return "", fmt.Errorf("path param %s: %w", field.name, err)
+			}
+			if formattedOK {
+				params[field.name] = formatted
+			}
+		case autoQuery:
+			if err := encodeQueryField(query.AddValue, field.query, value); err != nil {
+				return "", fmt.Errorf("query param %s: %w", field.name, err)
+			}
+		}
+	}
+
+	path, err := a.path.Expand(params)
+	if err != nil {
+		return "", err
+	}
+
+	encodedQuery := query.Encode()
+	if encodedQuery == "" {
+		return path, nil
+	}
+
+	if strings.Contains(path, "?") {
+		return path + "&" + encodedQuery, nil
+	}
+
+	return path + "?" + encodedQuery, nil
+}
+
+// Encode encodes the body field with the codec of its content type. Requests without
+// a body field, or with a nil body, have no body.
+func (a *AutoRequest) Encode() (io.Reader, error) {
+	for _, field := range a.fields {
+		if field.kind != autoBody {
+			continue
+		}
+
+		value, ok := a.fieldValue(field)
+		if !ok || isNilValue(value) {
+			return nil, nil
+		}
+
+		return a.codec.Encode(value.Interface())
+	}
+
+	return nil, nil
+}
+
+// ContentType returns the content type of the body field, if any.
+func (a *AutoRequest) ContentType() string {
+	return a.contentType
+}
+
+// RequestHeaders returns the header fields.
+func (a *AutoRequest) RequestHeaders() (http.Header, error) {
+	header := make(http.Header)
+
+	for _, field := range a.fields {
+		if field.kind != autoHeader {
+			continue
+		}
+
+		value, ok := a.fieldValue(field)
+		if !ok {
+			continue
+		}
+
+		err := encodeQueryField(func(_, formatted string) {
+			header.Add(field.name, formatted)
+		}, queryField{omitEmpty: field.query.omitEmpty, layout: field.query.layout, explode: true}, value)
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", field.name, err)
+		}
+	}
+
+	return header, nil
+}
+
+// fieldValue returns the value of a field. It returns false if the field is in a nil
+// embedded struct.
+func (a *AutoRequest) fieldValue(field autoField) (reflect.Value, bool) {
+	value, err := a.value.FieldByIndexErr(field.index)
+	return value, err == nil
+}
+
+func isNilValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
+
+func isAutoScalar(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if isQueryScalar(t) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isAutoScalarSlice(t reflect.Type) bool {
+	return (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && isAutoScalar(t.Elem())
+}
+
+func isAutoObject(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct || t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
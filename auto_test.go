@@ -0,0 +1,229 @@
+package restclientgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type autoFilter struct {
+	Status string `json:"status" xml:"status" url:"status"`
+}
+
+type autoTenant struct {
+	Tenant string `rest:"header=X-Tenant"`
+}
+
+type autoPostsRequest struct {
+	autoTenant
+	UserID string     `rest:"path=id"`
+	Limit  int        `rest:"query=limit,omitempty"`
+	Tags   []string   `rest:"query=tag,explode=false"`
+	Since  *time.Time `rest:"query=since" layout:"DateOnly"`
+	Scopes []string   `rest:"header=X-Scope"`
+	Filter autoFilter `rest:"body"`
+	Ignore string
+}
+
+func TestAuto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintf(w, "%s %s\n%s\n%s\n%s\n%s", req.Method, req.URL.RequestURI(), req.Header.Get("X-Tenant"),
+			strings.Join(req.Header.Values("X-Scope"), ","), req.Header.Get("Content-Type"), body)
+	}))
+	defer server.Close()
+
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	requestModel := &autoPostsRequest{
+		autoTenant: autoTenant{Tenant: "acme"},
+		UserID:     "a/b",
+		Tags:       []string{"x", "y"},
+		Since:      &since,
+		Scopes:     []string{"read", "write"},
+		Filter:     autoFilter{Status: "open"},
+	}
+
+	request, err := Auto("/users/{id}/posts", requestModel)
+	if err != nil {
+		t.Fatalf("Auto() error = %v", err)
+	}
+
+	response := &EchoResponse{}
+	if err := New(server.URL).Post(context.Background(), request, response); err != nil {
+		t.Fatalf("RestClient.Post() error = %v", err)
+	}
+
+	want := "POST /users/a%2Fb/posts?since=2024-03-01&tag=x%2Cy\nacme\nread,write\napplication/json\n" +
+		`{"status":"open"}`
+	if response.Body != want {
+		t.Errorf("RestClient.Post() = %q, want %q", response.Body, want)
+	}
+
+	// fields are read when the request is sent
+	requestModel.Limit = 5
+	path, err := request.Path()
+	if err != nil {
+		t.Fatalf("AutoRequest.Path() error = %v", err)
+	}
+
+	if !strings.Contains(path, "limit=5") {
+		t.Errorf("AutoRequest.Path() = %s, want limit=5", path)
+	}
+}
+
+func TestAuto_ContentType(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{
+			name: "xml",
+			v: &struct {
+				Filter autoFilter `rest:"body,contentType=application/xml"`
+			}{Filter: autoFilter{Status: "open"}},
+			want: "<autoFilter><status>open</status></autoFilter>",
+		},
+		{
+			name: "form",
+			v: &struct {
+				Filter *autoFilter `rest:"body,contentType=application/x-www-form-urlencoded"`
+			}{Filter: &autoFilter{Status: "open"}},
+			want: "status=open",
+		},
+		{
+			name: "no body",
+			v: &struct {
+				Filter *autoFilter `rest:"body"`
+			}{},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := Auto("/filters", tt.v)
+			if err != nil {
+				t.Fatalf("Auto() error = %v", err)
+			}
+
+			body, err := request.Encode()
+			if err != nil {
+				t.Fatalf("AutoRequest.Encode() error = %v", err)
+			}
+
+			var got string
+			if body != nil {
+				data, _ := io.ReadAll(body)
+				got = string(data)
+			}
+
+			if got != tt.want {
+				t.Errorf("AutoRequest.Encode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuto_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		path PathTemplate
+		v    any
+	}{
+		{
+			name: "not a struct",
+			path: "/users",
+			v:    "users",
+		},
+		{
+			name: "unknown kind",
+			path: "/users",
+			v: struct {
+				ID string `rest:"cookie=id"`
+			}{},
+		},
+		{
+			name: "missing name",
+			path: "/users",
+			v: struct {
+				ID string `rest:"query"`
+			}{},
+		},
+		{
+			name: "missing path field",
+			path: "/users/{id}",
+			v:    struct{}{},
+		},
+		{
+			name: "unused path field",
+			path: "/users",
+			v: struct {
+				ID string `rest:"path=id"`
+			}{},
+		},
+		{
+			name: "unsupported path type",
+			path: "/users/{id}",
+			v: struct {
+				ID []string `rest:"path=id"`
+			}{},
+		},
+		{
+			name: "duplicate body",
+			path: "/users",
+			v: struct {
+				A string `rest:"body"`
+				B string `rest:"body"`
+			}{},
+		},
+		{
+			name: "unknown content type",
+			path: "/users",
+			v: struct {
+				A string `rest:"body,contentType=application/unknown"`
+			}{},
+		},
+		{
+			name: "unknown query style",
+			path: "/users",
+			v: struct {
+				IDs []int `rest:"query=ids,style=matrix"`
+			}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Auto(tt.path, tt.v); !errors.Is(err, ErrAutoRequest) {
+				t.Errorf("Auto() error = %v, want %v", err, ErrAutoRequest)
+			}
+		})
+	}
+}
+
+func TestCodecFor(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        Codec
+	}{
+		{contentType: "application/json; charset=utf-8", want: JSONCodec{}},
+		{contentType: "application/problem+json", want: JSONCodec{}},
+		{contentType: "application/atom+xml", want: XMLCodec{}},
+		{contentType: "TEXT/PLAIN", want: TextCodec{}},
+		{contentType: "application/octet-stream", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			got, ok := CodecFor(tt.contentType)
+			if got != tt.want || ok != (tt.want != nil) {
+				t.Errorf("CodecFor() = %v, %v, want %v", got, ok, tt.want)
+			}
+		})
+	}
+}
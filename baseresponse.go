@@ -0,0 +1,107 @@
+package restclientgo
+
+import (
+	"io"
+	"time"
+)
+
+// DefaultMaxBodySize is the number of bytes of the raw body kept by BaseResponse
+// when its MaxBodySize is not set.
+const DefaultMaxBodySize = 64 * 1024
+
+// BaseResponse is an embeddable Response storing the status code, the headers, the
+// timing and the raw body of responses that are not decoded, such as error responses.
+// The raw body of those responses is capped to MaxBodySize bytes.
+//
+// Embedding types add their own Decode and AcceptContentType to decode successful
+// responses; alone, BaseResponse accepts JSON and keeps it, whole, as the raw body.
+type BaseResponse struct {
+	StatusCode int
+	Headers    Headers
+	RawBody    []byte
+	// Truncated is true if the raw body exceeded MaxBodySize.
+	Truncated bool
+	// ReceivedAt is the time the response headers were received.
+	ReceivedAt time.Time
+	// Timing is the timing of the request.
+	Timing Timing
+	// MaxBodySize is the maximum size of the raw body of responses that are not decoded,
+	// DefaultMaxBodySize if 0.
+	MaxBodySize int64
+}
+
+// Decode stores the whole body as the raw body.
+func (r *BaseResponse) Decode(body io.Reader) error {
+	rawBody, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	r.RawBody = rawBody
+	r.Truncated = false
+
+	return nil
+}
+
+// SetBody stores the body of a response that is not decoded, up to MaxBodySize bytes,
+// as the raw body.
+func (r *BaseResponse) SetBody(body io.Reader) error {
+	maxBodySize := r.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+
+	rawBody, err := io.ReadAll(io.LimitReader(body, maxBodySize+1))
+	if err != nil {
+		return err
+	}
+
+	r.Truncated = int64(len(rawBody)) > maxBodySize
+	if r.Truncated {
+		rawBody = rawBody[:maxBodySize]
+	}
+	r.RawBody = rawBody
+
+	return nil
+}
+
+// AcceptContentType returns application/json.
+func (r *BaseResponse) AcceptContentType() string {
+	return "application/json"
+}
+
+// SetStatusCode stores the status code.
+func (r *BaseResponse) SetStatusCode(code int) error {
+	r.StatusCode = code
+	return nil
+}
+
+// SetHeaders stores the headers and the time of arrival of the response.
+func (r *BaseResponse) SetHeaders(headers Headers) error {
+	r.Headers = headers
+	r.ReceivedAt = time.Now()
+	return nil
+}
+
+// SetTiming stores the timing of the request.
+func (r *BaseResponse) SetTiming(timing Timing) error {
+	r.Timing = timing
+	return nil
+}
+
+// IsSuccess reports whether the status code is 2xx.
+func (r *BaseResponse) IsSuccess() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// JSONResponse is a Response decoding JSON bodies into Data, with the status code,
+// headers and error bodies of BaseResponse.
+type JSONResponse[T any] struct {
+	BaseResponse
+	Data T
+}
+
+// Decode decodes the JSON body into Data.
+func (r *JSONResponse[T]) Decode(body io.Reader) error {
+	return JSONCodec{}.Decode(body, &r.Data)
+}
@@ -0,0 +1,80 @@
+package restclientgo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJSONResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Request-Id", "abc")
+		if strings.HasSuffix(req.URL.Path, "/2") {
+			http.Error(w, strings.Repeat("e", 100), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"userId":2,"title":"base","completed":true}`))
+	}))
+	defer server.Close()
+
+	type todo struct {
+		ID        int    `json:"id"`
+		UserID    int    `json:"userId"`
+		Title     string `json:"title"`
+		Completed bool   `json:"completed"`
+	}
+
+	restClient := New(server.URL)
+
+	response := &JSONResponse[todo]{}
+	if err := restClient.Get(context.Background(), &todoRequest{ID: "1"}, response); err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	if !response.IsSuccess() || response.Data != (todo{ID: 1, UserID: 2, Title: "base", Completed: true}) {
+		t.Errorf("RestClient.Get() = %+v", response)
+	}
+
+	if response.Headers["X-Request-Id"][0] != "abc" || response.ReceivedAt.IsZero() {
+		t.Errorf("RestClient.Get() headers = %v, received at %v", response.Headers, response.ReceivedAt)
+	}
+
+	if response.Timing.Start.IsZero() || response.Timing.Total <= 0 {
+		t.Errorf("RestClient.Get() timing = %+v", response.Timing)
+	}
+
+	errorResponse := &JSONResponse[todo]{BaseResponse: BaseResponse{MaxBodySize: 10}}
+	if err := restClient.Get(context.Background(), &todoRequest{ID: "2"}, errorResponse); err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	if errorResponse.StatusCode != http.StatusNotFound || errorResponse.IsSuccess() {
+		t.Errorf("RestClient.Get() status code = %d", errorResponse.StatusCode)
+	}
+
+	if string(errorResponse.RawBody) != strings.Repeat("e", 10) || !errorResponse.Truncated {
+		t.Errorf("RestClient.Get() raw body = %q, truncated %v", errorResponse.RawBody, errorResponse.Truncated)
+	}
+}
+
+func TestBaseResponse(t *testing.T) {
+	body := `{"data":"` + strings.Repeat("x", 100) + `"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	// successful bodies are not capped
+	response := &BaseResponse{MaxBodySize: 10}
+	if err := New(server.URL).Get(context.Background(), &todoRequest{ID: "1"}, response); err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	if string(response.RawBody) != body || response.Truncated {
+		t.Errorf("RestClient.Get() raw body = %q, truncated %v", response.RawBody, response.Truncated)
+	}
+}
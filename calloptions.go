@@ -0,0 +1,134 @@
+package restclientgo
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// CallOption overrides a client default for a single call.
+type CallOption func(*callOptions)
+
+// OptionsRequest is implemented by requests with their own call options. The options
+// of the request are applied before the options passed to the call.
+type OptionsRequest interface {
+	RequestOptions() []CallOption
+}
+
+type callOptions struct {
+	timeout          time.Duration
+	retryPolicy      *RetryPolicy
+	decodeOnError    bool
+	expectedStatuses []int
+	maxResponseSize  int64
+	dryRun           func(*DryRunResult) error
+}
+
+// WithTimeout sets the timeout of the call, including retries. 0 disables the timeout.
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithRetry sets the retry policy of the call.
+func WithRetry(policy RetryPolicy) CallOption {
+	return func(o *callOptions) {
+		o.retryPolicy = &policy
+	}
+}
+
+// WithoutRetry disables the retries of the call.
+func WithoutRetry() CallOption {
+	return func(o *callOptions) {
+		o.retryPolicy = nil
+	}
+}
+
+// WithDecodeOnError forces the response of the call to be decoded even if the status
+// code is >= 400.
+func WithDecodeOnError(decodeOnError bool) CallOption {
+	return func(o *callOptions) {
+		o.decodeOnError = decodeOnError
+	}
+}
+
+// WithExpectedStatus sets the status codes expected for the call. Responses with one
+// of them are decoded, even if >= 400, and any other status code is returned as an
+// ErrResponseStatus error.
+func WithExpectedStatus(codes ...int) CallOption {
+	return func(o *callOptions) {
+		o.expectedStatuses = codes
+	}
+}
+
+// WithMaxResponseSize limits the size of the response body of the call. Larger bodies
+// fail with ErrResponseSize. 0 disables the limit.
+func WithMaxResponseSize(size int64) CallOption {
+	return func(o *callOptions) {
+		o.maxResponseSize = size
+	}
+}
+
+// WithCallOptions sets the default options of the calls.
+func (r *RestClient) WithCallOptions(opts ...CallOption) *RestClient {
+	r.callOptions = opts
+	return r
+}
+
+func (r *RestClient) newCallOptions(request Request, opts []CallOption) *callOptions {
+	options := &callOptions{decodeOnError: r.forceDecodeOnError}
+
+	for _, opt := range r.callOptions {
+		opt(options)
+	}
+
+	if optionsRequest, ok := requestAs[OptionsRequest](request); ok {
+		for _, opt := range optionsRequest.RequestOptions() {
+			opt(options)
+		}
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// limitResponseSize fails responses declaring a body larger than size, and limits the
+// body of the others.
+func limitResponseSize(httpResponse *http.Response, size int64) error {
+	if httpResponse.ContentLength > size {
+		return fmt.Errorf("%w: %d bytes", ErrResponseSize, httpResponse.ContentLength)
+	}
+
+	httpResponse.Body = &limitedBody{ReadCloser: httpResponse.Body, remaining: size}
+
+	return nil
+}
+
+// limitedBody fails with ErrResponseSize once more than remaining bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrResponseSize
+	}
+
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), ErrResponseSize
+	}
+
+	return n, err
+}
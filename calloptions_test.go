@@ -0,0 +1,223 @@
+package restclientgo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type optionsRequest struct {
+	path    string
+	options []CallOption
+}
+
+func (r *optionsRequest) Path() (string, error)        { return r.path, nil }
+func (r *optionsRequest) Encode() (io.Reader, error)   { return nil, nil }
+func (r *optionsRequest) ContentType() string          { return "" }
+func (r *optionsRequest) RequestOptions() []CallOption { return r.options }
+
+func TestRestClient_CallOptions(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case "/flaky":
+			if attempts.Add(1) < 3 {
+				w.Header().Set("Retry-After", "0")
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+		case "/missing":
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("missing"))
+			return
+		case "/large":
+			w.Header().Set("Content-Type", "text/plain")
+			// unknown length, caught while reading
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte(strings.Repeat("a", 100)))
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	retry := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	tests := []struct {
+		name     string
+		client   *RestClient
+		method   string
+		request  *optionsRequest
+		opts     []CallOption
+		want     string
+		wantErr  error
+		attempts int32
+	}{
+		{
+			name:    "timeout",
+			client:  New(server.URL),
+			request: &optionsRequest{path: "slow"},
+			opts:    []CallOption{WithTimeout(50 * time.Millisecond)},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "client timeout overridden",
+			client:  New(server.URL).WithCallOptions(WithTimeout(50 * time.Millisecond)),
+			request: &optionsRequest{path: "slow"},
+			opts:    []CallOption{WithTimeout(0)},
+			want:    "ok",
+		},
+		{
+			name:     "retry",
+			client:   New(server.URL),
+			request:  &optionsRequest{path: "flaky"},
+			opts:     []CallOption{WithRetry(retry)},
+			want:     "ok",
+			attempts: 3,
+		},
+		{
+			name:     "post not retried",
+			client:   New(server.URL).WithCallOptions(WithRetry(retry)),
+			method:   http.MethodPost,
+			request:  &optionsRequest{path: "flaky"},
+			want:     "unavailable\n",
+			attempts: 1,
+		},
+		{
+			name:     "client retry disabled",
+			client:   New(server.URL).WithCallOptions(WithRetry(retry)),
+			request:  &optionsRequest{path: "flaky"},
+			opts:     []CallOption{WithoutRetry()},
+			want:     "unavailable\n",
+			attempts: 1,
+		},
+		{
+			name:    "expected status",
+			client:  New(server.URL),
+			request: &optionsRequest{path: "missing"},
+			opts:    []CallOption{WithExpectedStatus(http.StatusOK, http.StatusNotFound)},
+			want:    "missing",
+		},
+		{
+			name:    "unexpected status",
+			client:  New(server.URL),
+			request: &optionsRequest{path: "ok"},
+			opts:    []CallOption{WithExpectedStatus(http.StatusCreated)},
+			wantErr: ErrResponseStatus,
+		},
+		{
+			name:    "request expected status",
+			client:  New(server.URL),
+			request: &optionsRequest{"missing", []CallOption{WithExpectedStatus(http.StatusNotFound)}},
+			want:    "missing",
+		},
+		{
+			name:    "call options override request options",
+			client:  New(server.URL),
+			request: &optionsRequest{"missing", []CallOption{WithExpectedStatus(http.StatusNotFound)}},
+			opts:    []CallOption{WithExpectedStatus(http.StatusOK)},
+			wantErr: ErrResponseStatus,
+		},
+		{
+			name:    "max response size",
+			client:  New(server.URL),
+			request: &optionsRequest{path: "large"},
+			opts:    []CallOption{WithMaxResponseSize(10)},
+			want:    strings.Repeat("a", 10),
+			wantErr: ErrResponseSize,
+		},
+		{
+			name:    "max response size content length",
+			client:  New(server.URL).WithCallOptions(WithMaxResponseSize(1)),
+			request: &optionsRequest{path: "ok"},
+			wantErr: ErrResponseSize,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts.Store(0)
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			response := &EchoResponse{}
+			err := tt.client.Do(context.Background(), method, tt.request, response, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RestClient.Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if response.Body != tt.want {
+				t.Errorf("RestClient.Do() = %q, want %q", response.Body, tt.want)
+			}
+
+			if tt.attempts > 0 && attempts.Load() != tt.attempts {
+				t.Errorf("RestClient.Do() attempts = %d, want %d", attempts.Load(), tt.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_retryDelay(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 5, Backoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := []struct {
+		name       string
+		attempt    int
+		method     string
+		header     http.Header
+		statusCode int
+		err        error
+		retryAfter string
+		want       time.Duration
+		wantRetry  bool
+	}{
+		{name: "first retry", attempt: 1, statusCode: 503, want: 100 * time.Millisecond, wantRetry: true},
+		{name: "doubled", attempt: 3, statusCode: 429, want: 400 * time.Millisecond, wantRetry: true},
+		{name: "capped", attempt: 4, statusCode: 502, want: 800 * time.Millisecond, wantRetry: true},
+		{name: "retry after", attempt: 1, statusCode: 503, retryAfter: "2", want: time.Second, wantRetry: true},
+		{name: "not retryable", attempt: 1, statusCode: 500},
+		{name: "max attempts", attempt: 5, statusCode: 503},
+		{name: "transport error", attempt: 1, method: http.MethodPut, err: io.ErrUnexpectedEOF, want: 100 * time.Millisecond, wantRetry: true},
+		{name: "post transport error", attempt: 1, method: http.MethodPost, err: io.ErrUnexpectedEOF},
+		{name: "post", attempt: 1, method: http.MethodPost, statusCode: 503},
+		{
+			name:       "post with idempotency key",
+			attempt:    1,
+			method:     http.MethodPatch,
+			header:     http.Header{"Idempotency-Key": {"key"}},
+			statusCode: 503,
+			want:       100 * time.Millisecond,
+			wantRetry:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpRequest := &http.Request{Method: tt.method, Header: tt.header}
+
+			var httpResponse *http.Response
+			if tt.err == nil {
+				httpResponse = &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+				if tt.retryAfter != "" {
+					httpResponse.Header.Set("Retry-After", tt.retryAfter)
+				}
+			}
+
+			got, retry := policy.retryDelay(tt.attempt, httpRequest, httpResponse, tt.err)
+			if got != tt.want || retry != tt.wantRetry {
+				t.Errorf("RetryPolicy.retryDelay() = %v, %v, want %v, %v", got, retry, tt.want, tt.wantRetry)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package restclientgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Codec encodes request bodies and decodes response bodies of a content type.
+type Codec interface {
+	Encode(v any) (io.Reader, error)
+	Decode(body io.Reader, v any) error
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]Codec{
+		"application/json":                  JSONCodec{},
+		"application/xml":                   XMLCodec{},
+		"text/xml":                          XMLCodec{},
+		"application/x-www-form-urlencoded": FormCodec{},
+		"text/plain":                        TextCodec{},
+	}
+)
+
+// RegisterCodec registers the codec of the given media type. JSON, XML, form and plain
+// text codecs are registered by default.
+func RegisterCodec(mediaType string, codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	codecs[strings.ToLower(mediaType)] = codec
+}
+
+// CodecFor returns the codec registered for a content type. Parameters such as charset
+// are ignored, and media types with a +json or +xml suffix, as in
+// application/problem+json, fall back to the JSON and XML codecs.
+func CodecFor(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	if codec, ok := codecs[mediaType]; ok {
+		return codec, true
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return codecs["application/json"], true
+	case strings.HasSuffix(mediaType, "+xml"):
+		return codecs["application/xml"], true
+	}
+
+	return nil, false
+}
+
+// JSONCodec encodes and decodes JSON bodies.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(v any) (io.Reader, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+func (JSONCodec) Decode(body io.Reader, v any) error {
+	return json.NewDecoder(body).Decode(v)
+}
+
+// XMLCodec encodes and decodes XML bodies.
+type XMLCodec struct{}
+
+func (XMLCodec) Encode(v any) (io.Reader, error) {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+func (XMLCodec) Decode(body io.Reader, v any) error {
+	return xml.NewDecoder(body).Decode(v)
+}
+
+// FormCodec encodes structs with EncodeQuery and decodes form bodies with DecodeQuery.
+// url.Values are encoded and decoded as they are.
+type FormCodec struct{}
+
+func (FormCodec) Encode(v any) (io.Reader, error) {
+	switch values := v.(type) {
+	case url.Values:
+		return strings.NewReader(values.Encode()), nil
+	case *url.Values:
+		return strings.NewReader(values.Encode()), nil
+	}
+
+	values, err := EncodeQuery(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.NewReader(values.Encode()), nil
+}
+
+func (FormCodec) Decode(body io.Reader, v any) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return err
+	}
+
+	if target, ok := v.(*url.Values); ok {
+		*target = values
+		return nil
+	}
+
+	return DecodeQuery(values, v)
+}
+
+// TextCodec encodes strings, byte slices, fmt.Stringer values and readers as they are,
+// and decodes bodies into strings and byte slices.
+type TextCodec struct{}
+
+func (TextCodec) Encode(v any) (io.Reader, error) {
+	switch value := v.(type) {
+	case string:
+		return strings.NewReader(value), nil
+	case *string:
+		return strings.NewReader(*value), nil
+	case []byte:
+		return bytes.NewReader(value), nil
+	case io.Reader:
+		return value, nil
+	case fmt.Stringer:
+		return strings.NewReader(value.String()), nil
+	default:
+		return nil, fmt.Errorf("unsupported text body %T", v)
+	}
+}
+
+func (TextCodec) Decode(body io.Reader, v any) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	switch target := v.(type) {
+	case *string:
+		*target = string(data)
+	case *[]byte:
+		*target = data
+	default:
+		return fmt.Errorf("unsupported text body %T", v)
+	}
+
+	return nil
+}
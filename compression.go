@@ -0,0 +1,162 @@
+package restclientgo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+)
+
+// WithRequestCompression compresses the encoded request bodies larger than threshold
+// bytes with encoding, EncodingGzip or EncodingDeflate, and sets the Content-Encoding header.
+// A threshold of 0, or a negative one, compresses every non-empty body.
+func (r *RestClient) WithRequestCompression(encoding string, threshold int) *RestClient {
+	r.requestEncoding = encoding
+	r.compressionThreshold = max(threshold, 0)
+	return r
+}
+
+// WithResponseDecompression enables or disables the decompression of gzip and deflate
+// encoded responses. Decompression is enabled by default.
+func (r *RestClient) WithResponseDecompression(decompress bool) *RestClient {
+	r.skipDecompression = !decompress
+	return r
+}
+
+// compressRequestBody compresses body if it is larger than the compression threshold.
+// It returns whether the body has been compressed.
+func (r *RestClient) compressRequestBody(body io.Reader) (io.Reader, bool, error) {
+	if r.requestEncoding == "" || body == nil {
+		return body, false, nil
+	}
+
+	if r.requestEncoding != EncodingGzip && r.requestEncoding != EncodingDeflate {
+		return nil, false, fmt.Errorf("unsupported content encoding %s", r.requestEncoding)
+	}
+
+	head := make([]byte, r.compressionThreshold+1)
+	n, err := io.ReadFull(body, head)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		closeBody(body)
+		return bytes.NewReader(head[:n]), false, nil
+	}
+	if err != nil {
+		closeBody(body)
+		return nil, false, err
+	}
+
+	reader, writer := io.Pipe()
+	return &pipeBody{
+		reader: reader,
+		start: func() {
+			defer closeBody(body)
+			writer.CloseWithError(r.compress(writer, io.MultiReader(bytes.NewReader(head), body)))
+		},
+		abort: func() {
+			closeBody(body)
+		},
+	}, true, nil
+}
+
+func (r *RestClient) compress(w io.Writer, body io.Reader) error {
+	var compressor io.WriteCloser
+	if r.requestEncoding == EncodingGzip {
+		compressor = gzip.NewWriter(w)
+	} else {
+		compressor = zlib.NewWriter(w)
+	}
+
+	_, err := io.Copy(compressor, body)
+	if err != nil {
+		return err
+	}
+
+	return compressor.Close()
+}
+
+func closeBody(body io.Reader) {
+	if closer, ok := body.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
+
+// decompressResponse replaces the body of a gzip or deflate encoded response with
+// its decompressed content, as the http.Transport does when it requests compression.
+func decompressResponse(httpResponse *http.Response) {
+	if httpResponse.Uncompressed {
+		return
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(httpResponse.Header.Get("Content-Encoding")))
+	switch encoding {
+	case EncodingGzip, "x-gzip", EncodingDeflate:
+	default:
+		return
+	}
+
+	httpResponse.Body = &decompressingBody{body: httpResponse.Body, encoding: encoding}
+	httpResponse.Header.Del("Content-Encoding")
+	httpResponse.Header.Del("Content-Length")
+	httpResponse.ContentLength = -1
+	httpResponse.Uncompressed = true
+}
+
+// decompressingBody creates the decompressor on the first Read, so that empty
+// bodies are not required to hold a valid compression header.
+type decompressingBody struct {
+	body     io.ReadCloser
+	encoding string
+	reader   io.Reader
+	err      error
+}
+
+func (d *decompressingBody) Read(p []byte) (int, error) {
+	if d.reader == nil && d.err == nil {
+		d.reader, d.err = newDecompressor(d.encoding, d.body)
+	}
+
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	return d.reader.Read(p)
+}
+
+func (d *decompressingBody) Close() error {
+	if closer, ok := d.reader.(io.Closer); ok {
+		_ = closer.Close()
+	}
+
+	return d.body.Close()
+}
+
+func newDecompressor(encoding string, body io.Reader) (io.Reader, error) {
+	if encoding != EncodingDeflate {
+		return gzip.NewReader(body)
+	}
+
+	// Some servers send raw deflate data instead of the zlib format required by
+	// the HTTP deflate encoding.
+	bufferedBody := bufio.NewReader(body)
+	header, err := bufferedBody.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(bufferedBody)
+	}
+
+	return flate.NewReader(bufferedBody), nil
+}
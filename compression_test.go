@@ -0,0 +1,289 @@
+package restclientgo
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+type echoRequest struct {
+	body string
+}
+
+func (r *echoRequest) Path() (string, error)      { return "/echo", nil }
+func (r *echoRequest) Encode() (io.Reader, error) { return strings.NewReader(r.body), nil }
+func (r *echoRequest) ContentType() string        { return "text/plain" }
+
+type EchoResponse struct {
+	Body string
+}
+
+func (r *EchoResponse) Decode(body io.Reader) error {
+	b, err := io.ReadAll(body)
+	r.Body = string(b)
+	return err
+}
+func (r *EchoResponse) SetBody(body io.Reader) error { return r.Decode(body) }
+func (r *EchoResponse) AcceptContentType() string    { return "text/plain" }
+func (r *EchoResponse) SetStatusCode(code int) error { return nil }
+func (r *EchoResponse) SetHeaders(headers Headers) error {
+	_ = headers
+	return nil
+}
+
+type StreamingEchoResponse struct {
+	EchoResponse
+	Chunks []string
+}
+
+func (r *StreamingEchoResponse) StreamCallback() StreamCallback {
+	return func(line []byte) error {
+		r.Chunks = append(r.Chunks, string(line))
+		return nil
+	}
+}
+
+// newCompressingEchoServer echoes the decompressed request body, compressed with
+// the encoding requested in the X-Response-Encoding header. The request encoding
+// is echoed in the X-Request-Encoding response header.
+func newCompressingEchoServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body io.Reader = req.Body
+		switch req.Header.Get("Content-Encoding") {
+		case EncodingGzip:
+			body, _ = gzip.NewReader(req.Body)
+		case EncodingDeflate:
+			body, _ = zlib.NewReader(req.Body)
+		}
+
+		// read the whole request body before writing the response
+		content, _ := io.ReadAll(body)
+		body = bytes.NewReader(content)
+
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Request-Encoding", req.Header.Get("Content-Encoding"))
+
+		var writer io.WriteCloser
+		switch req.Header.Get("X-Response-Encoding") {
+		case EncodingGzip:
+			writer = gzip.NewWriter(w)
+			w.Header().Set("Content-Encoding", EncodingGzip)
+		case EncodingDeflate:
+			writer = zlib.NewWriter(w)
+			w.Header().Set("Content-Encoding", EncodingDeflate)
+		case "raw-deflate":
+			writer, _ = flate.NewWriter(w, flate.DefaultCompression)
+			w.Header().Set("Content-Encoding", EncodingDeflate)
+		default:
+			_, _ = io.Copy(w, body)
+			return
+		}
+
+		_, _ = io.Copy(writer, body)
+		_ = writer.Close()
+	}))
+}
+
+func TestRestClient_Compression(t *testing.T) {
+	server := newCompressingEchoServer()
+	defer server.Close()
+
+	large := strings.Repeat("compress me\n", 100)
+
+	tests := []struct {
+		name                string
+		requestEncoding     string
+		threshold           int
+		responseEncoding    string
+		body                string
+		wantRequestEncoding string
+	}{
+		{
+			name:                "gzip request",
+			requestEncoding:     EncodingGzip,
+			threshold:           100,
+			body:                large,
+			wantRequestEncoding: EncodingGzip,
+		},
+		{
+			name:                "deflate request",
+			requestEncoding:     EncodingDeflate,
+			threshold:           100,
+			body:                large,
+			wantRequestEncoding: EncodingDeflate,
+		},
+		{
+			name:                "negative threshold",
+			requestEncoding:     EncodingGzip,
+			threshold:           -5,
+			body:                "small",
+			wantRequestEncoding: EncodingGzip,
+		},
+		{
+			name:            "below threshold",
+			requestEncoding: EncodingGzip,
+			threshold:       len(large),
+			body:            large,
+		},
+		{
+			name:             "gzip response",
+			responseEncoding: EncodingGzip,
+			body:             large,
+		},
+		{
+			name:             "deflate response",
+			responseEncoding: EncodingDeflate,
+			body:             large,
+		},
+		{
+			name:             "raw deflate response",
+			responseEncoding: "raw-deflate",
+			body:             large,
+		},
+		{
+			name:             "empty gzip response",
+			responseEncoding: EncodingGzip,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requestEncoding string
+			restClient := New(server.URL).
+				WithRequestCompression(tt.requestEncoding, tt.threshold).
+				WithRequestModifier(func(req *http.Request) *http.Request {
+					req.Header.Set("X-Response-Encoding", tt.responseEncoding)
+					return req
+				}).
+				WithHTTPClient(&http.Client{Transport: &headerCapturingTransport{
+					header:  "X-Request-Encoding",
+					capture: &requestEncoding,
+				}})
+
+			response := &EchoResponse{}
+			if err := restClient.Post(context.Background(), &echoRequest{body: tt.body}, response); err != nil {
+				t.Fatalf("RestClient.Post() error = %v", err)
+			}
+
+			if response.Body != tt.body {
+				t.Errorf("RestClient.Post() body = %q, want %q", response.Body, tt.body)
+			}
+
+			if requestEncoding != tt.wantRequestEncoding {
+				t.Errorf("RestClient.Post() request encoding = %q, want %q", requestEncoding, tt.wantRequestEncoding)
+			}
+		})
+	}
+}
+
+func TestRestClient_CompressionStream(t *testing.T) {
+	server := newCompressingEchoServer()
+	defer server.Close()
+
+	restClient := New(server.URL).WithRequestModifier(func(req *http.Request) *http.Request {
+		req.Header.Set("X-Response-Encoding", EncodingGzip)
+		return req
+	})
+
+	response := &StreamingEchoResponse{}
+	if err := restClient.Post(context.Background(), &echoRequest{body: "first\nsecond\n"}, response); err != nil {
+		t.Fatalf("RestClient.Post() error = %v", err)
+	}
+
+	if strings.Join(response.Chunks, ",") != "first,second" {
+		t.Errorf("RestClient.Post() chunks = %v, want [first second]", response.Chunks)
+	}
+}
+
+// headerCapturingTransport captures a response header before any processing of the client.
+type headerCapturingTransport struct {
+	header  string
+	capture *string
+}
+
+func (h *headerCapturingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	response, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	*h.capture = response.Header.Get(h.header)
+	body, err := io.ReadAll(response.Body)
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	return response, err
+}
+
+// closeTrackingFS counts the files left open.
+type closeTrackingFS struct {
+	fstest.MapFS
+	open *atomic.Int32
+}
+
+func (f closeTrackingFS) Open(name string) (fs.File, error) {
+	file, err := f.MapFS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	f.open.Add(1)
+
+	return &closeTrackingFile{File: file, open: f.open}, nil
+}
+
+type closeTrackingFile struct {
+	fs.File
+	open *atomic.Int32
+}
+
+func (f *closeTrackingFile) Close() error {
+	f.open.Add(-1)
+	return f.File.Close()
+}
+
+func TestRestClient_CompressionUnsentBody(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// requests to the closed listener fail before the body is sent
+	deadEndpoint := "http://" + listener.Addr().String()
+	listener.Close()
+
+	var open atomic.Int32
+	fsys := closeTrackingFS{MapFS: fstest.MapFS{"data.txt": {Data: []byte(strings.Repeat("data", 1024))}}, open: &open}
+
+	restClient := New(deadEndpoint).WithRequestCompression(EncodingGzip, 512)
+
+	goroutines := runtime.NumGoroutine()
+	for range 20 {
+		request := NewMultipartRequest("/upload").AddFileFS("file", fsys, "data.txt")
+		if err := restClient.Post(context.Background(), request, &EchoResponse{}); err == nil {
+			t.Fatal("RestClient.Post() error = nil")
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for (runtime.NumGoroutine() > goroutines+2 || open.Load() != 0) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got := runtime.NumGoroutine(); got > goroutines+2 {
+		t.Errorf("goroutines = %d, want at most %d", got, goroutines+2)
+	}
+
+	if open.Load() != 0 {
+		t.Errorf("open files = %d, want 0", open.Load())
+	}
+}
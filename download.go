@@ -0,0 +1,323 @@
+package restclientgo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultMaxResumes = 3
+	maxErrorBodySize  = 4 * 1024
+)
+
+// DownloadResponse is a Response writing the body to an io.WriterAt. Use it with
+// RestClient.Download to resume interrupted transfers.
+type DownloadResponse struct {
+	HTTPStatusCode int
+	Headers        Headers
+	// ErrorBody holds the first bytes of the body of an error response.
+	ErrorBody []byte
+
+	writer     io.WriterAt
+	size       int64
+	hash       hash.Hash
+	checksum   []byte
+	maxResumes int
+
+	start        int64
+	end          int64
+	written      int64
+	total        int64
+	validator    string
+	acceptRanges bool
+	interrupted  bool
+}
+
+// NewDownloadResponse creates a new DownloadResponse writing to writer.
+func NewDownloadResponse(writer io.WriterAt) *DownloadResponse {
+	return &DownloadResponse{
+		writer:     writer,
+		size:       -1,
+		end:        -1,
+		total:      -1,
+		maxResumes: defaultMaxResumes,
+	}
+}
+
+// WithSize sets the expected size of the download.
+func (d *DownloadResponse) WithSize(size int64) *DownloadResponse {
+	d.size = size
+	return d
+}
+
+// WithChecksum verifies that the downloaded content hashed with h matches checksum.
+func (d *DownloadResponse) WithChecksum(h hash.Hash, checksum []byte) *DownloadResponse {
+	d.hash = h
+	d.checksum = checksum
+	return d
+}
+
+// WithMaxResumes sets how many times an interrupted download is resumed.
+func (d *DownloadResponse) WithMaxResumes(maxResumes int) *DownloadResponse {
+	d.maxResumes = maxResumes
+	return d
+}
+
+// Written returns the number of bytes written.
+func (d *DownloadResponse) Written() int64 {
+	return d.written
+}
+
+// Decode writes the body to the writer.
+func (d *DownloadResponse) Decode(body io.Reader) error {
+	return d.SetBody(body)
+}
+
+// SetBody writes the body to the writer, or stores it in ErrorBody if the status code is >= 400.
+func (d *DownloadResponse) SetBody(body io.Reader) error {
+	if d.HTTPStatusCode >= http.StatusBadRequest {
+		var err error
+		d.ErrorBody, err = io.ReadAll(io.LimitReader(body, maxErrorBodySize))
+		return err
+	}
+
+	return d.write(body)
+}
+
+// AcceptContentType returns an empty string as any content type is accepted.
+func (d *DownloadResponse) AcceptContentType() string {
+	return ""
+}
+
+// SetStatusCode sets the HTTP response status code.
+func (d *DownloadResponse) SetStatusCode(code int) error {
+	d.HTTPStatusCode = code
+	return nil
+}
+
+// SetHeaders sets the HTTP response headers.
+func (d *DownloadResponse) SetHeaders(headers Headers) error {
+	d.Headers = headers
+
+	header := http.Header(headers)
+	if etag := header.Get("ETag"); etag != "" {
+		d.validator = etag
+	} else if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		d.validator = lastModified
+	}
+	d.acceptRanges = strings.Contains(header.Get("Accept-Ranges"), "bytes")
+
+	return nil
+}
+
+func (d *DownloadResponse) write(body io.Reader) error {
+	header := http.Header(d.Headers)
+
+	if d.HTTPStatusCode == http.StatusPartialContent {
+		start, total, err := parseContentRange(header.Get("Content-Range"))
+		if err != nil {
+			return err
+		}
+
+		if start != d.start+d.written {
+			return fmt.Errorf("%w: content range starts at %d, want %d", ErrDownload, start, d.start+d.written)
+		}
+		d.total = total
+	} else {
+		if d.start > 0 {
+			return fmt.Errorf("%w: byte ranges not supported", ErrDownload)
+		}
+
+		d.end = -1
+		d.written = 0
+		d.total = -1
+		if d.hash != nil {
+			d.hash.Reset()
+		}
+
+		if contentLength, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil {
+			d.total = contentLength
+		}
+	}
+
+	var dst io.Writer = io.NewOffsetWriter(d.writer, d.start+d.written)
+	if d.hash != nil {
+		dst = io.MultiWriter(dst, d.hash)
+	}
+
+	reader := &errorRecordingReader{reader: body}
+	n, err := io.Copy(dst, reader)
+	d.written += n
+	if err != nil {
+		d.interrupted = reader.err != nil
+		return err
+	}
+
+	if d.end >= 0 {
+		return d.verifyRange()
+	}
+
+	return d.verify()
+}
+
+func (d *DownloadResponse) verifyRange() error {
+	end := d.end
+	if d.total >= 0 && end >= d.total {
+		end = d.total - 1
+	}
+
+	if d.written != end-d.start+1 {
+		return fmt.Errorf("%w: downloaded %d bytes of range %d-%d", ErrDownload, d.written, d.start, end)
+	}
+
+	return nil
+}
+
+func (d *DownloadResponse) verify() error {
+	size := d.size
+	if size < 0 {
+		size = d.total
+	}
+
+	if size >= 0 && d.written != size {
+		return fmt.Errorf("%w: downloaded %d bytes, want %d", ErrDownload, d.written, size)
+	}
+
+	if d.hash != nil && !bytes.Equal(d.hash.Sum(nil), d.checksum) {
+		return fmt.Errorf("%w: checksum mismatch", ErrDownload)
+	}
+
+	if truncater, ok := d.writer.(interface{ Truncate(size int64) error }); ok {
+		return truncater.Truncate(d.written)
+	}
+
+	return nil
+}
+
+func (d *DownloadResponse) canResume() bool {
+	if !d.interrupted {
+		return false
+	}
+
+	return d.written == 0 || (d.acceptRanges && d.validator != "")
+}
+
+// Download performs a GET request writing the body to the download response. If the
+// transfer is interrupted and the server accepts byte ranges, the download is resumed
+// from the last written byte with a Range request validated against the ETag.
+func (r *RestClient) Download(ctx context.Context, request Request, response *DownloadResponse) error {
+	for resumes := 0; ; resumes++ {
+		var downloadRequest Request = request
+		if response.written > 0 || response.start > 0 || response.end >= 0 {
+			downloadRequest = &rangeRequest{
+				Request:   request,
+				start:     response.start + response.written,
+				end:       response.end,
+				validator: response.validator,
+			}
+		}
+
+		response.interrupted = false
+		err := r.do(ctx, http.MethodGet, downloadRequest, response)
+		if err == nil {
+			if response.HTTPStatusCode >= http.StatusBadRequest {
+				return fmt.Errorf("%w: unexpected status code %d", ErrDownload, response.HTTPStatusCode)
+			}
+			return nil
+		}
+
+		if !response.canResume() || resumes >= response.maxResumes || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// rangeRequest requests the bytes of the wrapped request from start to end, or
+// to the end of the content if end is -1.
+type rangeRequest struct {
+	Request
+	start     int64
+	end       int64
+	validator string
+}
+
+func (r *rangeRequest) RequestHeaders() (http.Header, error) {
+	header, err := wrappedRequestHeaders(r.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	byteRange := fmt.Sprintf("bytes=%d-", r.start)
+	if r.end >= 0 {
+		byteRange += strconv.FormatInt(r.end, 10)
+	}
+
+	header.Set("Range", byteRange)
+	if r.validator != "" {
+		header.Set("If-Range", r.validator)
+	}
+
+	return header, nil
+}
+
+func (r *rangeRequest) unwrapRequest() Request {
+	return r.Request
+}
+
+// parseContentRange parses a "bytes start-end/total" Content-Range header. total is
+// -1 if unknown.
+func parseContentRange(contentRange string) (start, total int64, err error) {
+	invalid := fmt.Errorf("%w: invalid content range %q", ErrDownload, contentRange)
+
+	unit, byteRange, found := strings.Cut(contentRange, " ")
+	if !found || unit != "bytes" {
+		return 0, 0, invalid
+	}
+
+	byteRange, size, found := strings.Cut(byteRange, "/")
+	if !found {
+		return 0, 0, invalid
+	}
+
+	first, _, found := strings.Cut(byteRange, "-")
+	if !found {
+		return 0, 0, invalid
+	}
+
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, invalid
+	}
+
+	total = -1
+	if size != "*" {
+		total, err = strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			return 0, 0, invalid
+		}
+	}
+
+	return start, total, nil
+}
+
+// errorRecordingReader records the error returned by the wrapped reader.
+type errorRecordingReader struct {
+	reader io.Reader
+	err    error
+}
+
+func (e *errorRecordingReader) Read(p []byte) (int, error) {
+	n, err := e.reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		e.err = err
+	}
+
+	return n, err
+}
@@ -0,0 +1,111 @@
+package restclientgo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// DownloadParallel downloads the body of request in concurrency byte ranges fetched
+// at the same time. The size of the body is learnt from a range probe of its first
+// byte; if the server ignores byte ranges the body is downloaded with a single request.
+// The writer of the response must support concurrent writes and, if a checksum is set,
+// implement io.ReaderAt.
+func (r *RestClient) DownloadParallel(
+	ctx context.Context,
+	request Request,
+	response *DownloadResponse,
+	concurrency int,
+) error {
+	reader, isReaderAt := response.writer.(io.ReaderAt)
+	if response.hash != nil && !isReaderAt {
+		return fmt.Errorf("%w: checksum verification requires an io.ReaderAt", ErrDownload)
+	}
+
+	response.end = 0
+	err := r.Download(ctx, request, response)
+	if err != nil {
+		return err
+	}
+
+	if response.HTTPStatusCode != http.StatusPartialContent {
+		return nil
+	}
+
+	response.end = -1
+	if response.total < 0 {
+		return fmt.Errorf("%w: unknown content size", ErrDownload)
+	}
+
+	err = r.downloadChunks(ctx, request, response, concurrency)
+	if err != nil {
+		return err
+	}
+	response.written = response.total
+
+	if response.hash != nil {
+		response.hash.Reset()
+		_, err = io.Copy(response.hash, io.NewSectionReader(reader, 0, response.total))
+		if err != nil {
+			return err
+		}
+	}
+
+	return response.verify()
+}
+
+// downloadChunks downloads the bytes following the first one in concurrency chunks.
+func (r *RestClient) downloadChunks(
+	ctx context.Context,
+	request Request,
+	response *DownloadResponse,
+	concurrency int,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	concurrency = max(concurrency, 1)
+	chunkSize := (response.total - 1 + int64(concurrency) - 1) / int64(concurrency)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency)
+	for start := int64(1); start < response.total; start += chunkSize {
+		chunk := &DownloadResponse{
+			writer:     response.writer,
+			size:       -1,
+			total:      -1,
+			maxResumes: response.maxResumes,
+			start:      start,
+			end:        min(start+chunkSize, response.total) - 1,
+			validator:  response.validator,
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := r.downloadChunk(ctx, request, chunk)
+			if err != nil {
+				errs <- err
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	return <-errs
+}
+
+// downloadChunk downloads a chunk retrying from the last written byte on failure.
+func (r *RestClient) downloadChunk(ctx context.Context, request Request, chunk *DownloadResponse) error {
+	for retries := 0; ; retries++ {
+		err := r.Download(ctx, request, chunk)
+		if err == nil || retries >= chunk.maxResumes || ctx.Err() != nil {
+			return err
+		}
+	}
+}
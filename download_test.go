@@ -0,0 +1,252 @@
+package restclientgo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type downloadRequest struct{}
+
+func (r *downloadRequest) Path() (string, error)      { return "/file", nil }
+func (r *downloadRequest) Encode() (io.Reader, error) { return nil, nil }
+func (r *downloadRequest) ContentType() string        { return "" }
+
+// newFlakyServer serves content interrupting the first response after half of the body.
+func newFlakyServer(content []byte, firstETag, nextETag string, acceptRanges bool) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if requests.Add(1) == 1 {
+			if acceptRanges {
+				w.Header().Set("Accept-Ranges", "bytes")
+				w.Header().Set("ETag", firstETag)
+			}
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			_, _ = w.Write(content[:len(content)/2])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+
+		w.Header().Set("ETag", nextETag)
+		http.ServeContent(w, req, "", time.Time{}, bytes.NewReader(content))
+	}))
+
+	return server, &requests
+}
+
+func TestRestClient_Download(t *testing.T) {
+	content := bytes.Repeat([]byte("restclientgo"), 10000)
+	checksum := sha256.Sum256(content)
+
+	tests := []struct {
+		name         string
+		firstETag    string
+		nextETag     string
+		acceptRanges bool
+		checksum     []byte
+		wantRequests int32
+		wantErr      bool
+	}{
+		{
+			name:         "resume",
+			firstETag:    `"v1"`,
+			nextETag:     `"v1"`,
+			acceptRanges: true,
+			checksum:     checksum[:],
+			wantRequests: 2,
+		},
+		{
+			name:         "restart on changed etag",
+			firstETag:    `"v1"`,
+			nextETag:     `"v2"`,
+			acceptRanges: true,
+			checksum:     checksum[:],
+			wantRequests: 2,
+		},
+		{
+			name:         "ranges not supported",
+			wantRequests: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "checksum mismatch",
+			firstETag:    `"v1"`,
+			nextETag:     `"v1"`,
+			acceptRanges: true,
+			checksum:     []byte("invalid"),
+			wantRequests: 2,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newFlakyServer(content, tt.firstETag, tt.nextETag, tt.acceptRanges)
+			defer server.Close()
+
+			file, err := os.CreateTemp(t.TempDir(), "download")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			response := NewDownloadResponse(file)
+			if tt.checksum != nil {
+				response.WithChecksum(sha256.New(), tt.checksum)
+			}
+
+			err = New(server.URL).Download(context.Background(), &downloadRequest{}, response)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RestClient.Download() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("RestClient.Download() requests = %d, want %d", got, tt.wantRequests)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			got, err := os.ReadFile(file.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, content) {
+				t.Errorf("RestClient.Download() wrote %d bytes, want %d", len(got), len(content))
+			}
+		})
+	}
+}
+
+func TestRestClient_DownloadStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	file, err := os.CreateTemp(t.TempDir(), "download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	response := NewDownloadResponse(file)
+	err = New(server.URL).Download(context.Background(), &downloadRequest{}, response)
+	if !errors.Is(err, ErrDownload) {
+		t.Fatalf("RestClient.Download() error = %v, want %v", err, ErrDownload)
+	}
+
+	if string(response.ErrorBody) != "not found\n" {
+		t.Errorf("DownloadResponse.ErrorBody = %q, want %q", response.ErrorBody, "not found\n")
+	}
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		contentRange string
+		wantStart    int64
+		wantTotal    int64
+		wantErr      bool
+	}{
+		{contentRange: "bytes 100-199/1000", wantStart: 100, wantTotal: 1000},
+		{contentRange: "bytes 0-99/*", wantStart: 0, wantTotal: -1},
+		{contentRange: "items 0-99/1000", wantErr: true},
+		{contentRange: "bytes */1000", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentRange, func(t *testing.T) {
+			start, total, err := parseContentRange(tt.contentRange)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseContentRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if start != tt.wantStart || total != tt.wantTotal {
+				t.Errorf("parseContentRange() = %d, %d, want %d, %d", start, total, tt.wantStart, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestRestClient_DownloadParallel(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 10007)
+	checksum := sha256.Sum256(content)
+
+	tests := []struct {
+		name         string
+		handler      func(requests int32, w http.ResponseWriter, req *http.Request)
+		wantRequests int32
+	}{
+		{
+			name: "ranges",
+			handler: func(_ int32, w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("ETag", `"v1"`)
+				http.ServeContent(w, req, "", time.Time{}, bytes.NewReader(content))
+			},
+			wantRequests: 5,
+		},
+		{
+			name: "chunk retry",
+			handler: func(requests int32, w http.ResponseWriter, req *http.Request) {
+				if requests == 2 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Header().Set("ETag", `"v1"`)
+				http.ServeContent(w, req, "", time.Time{}, bytes.NewReader(content))
+			},
+			wantRequests: 6,
+		},
+		{
+			name: "ranges ignored",
+			handler: func(_ int32, w http.ResponseWriter, req *http.Request) {
+				_, _ = w.Write(content)
+			},
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				tt.handler(requests.Add(1), w, req)
+			}))
+			defer server.Close()
+
+			file, err := os.CreateTemp(t.TempDir(), "download")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			response := NewDownloadResponse(file).WithChecksum(sha256.New(), checksum[:])
+
+			err = New(server.URL).DownloadParallel(context.Background(), &downloadRequest{}, response, 4)
+			if err != nil {
+				t.Fatalf("RestClient.DownloadParallel() error = %v", err)
+			}
+
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("RestClient.DownloadParallel() requests = %d, want %d", got, tt.wantRequests)
+			}
+
+			got, err := os.ReadFile(file.Name())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, content) {
+				t.Errorf("RestClient.DownloadParallel() wrote %d bytes, want %d", len(got), len(content))
+			}
+		})
+	}
+}
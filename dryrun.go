@@ -0,0 +1,118 @@
+package restclientgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// DryRunResult is the request a call would send in dry-run mode.
+type DryRunResult struct {
+	Method string
+	URL    string
+	Header http.Header
+	// Host is the Host header of the request, empty for the host of the URL.
+	Host string
+	// Body is the encoded body, after compression and the request modifier.
+	Body []byte
+	// Request is the built request, with a body that can be read again. It is detached
+	// from the context of the call, which ends with the call: attach one with
+	// http.Request.WithContext before sending it.
+	Request *http.Request
+}
+
+// WithDryRun builds the request of the call without sending it, passing it to callback.
+// The call returns the error of callback, and the response is left untouched. Use it with
+// RestClient.WithCallOptions to put the client in dry-run mode.
+func WithDryRun(callback func(*DryRunResult) error) CallOption {
+	return func(o *callOptions) {
+		o.dryRun = callback
+	}
+}
+
+// WithDryRunResult builds the request of the call without sending it, storing it in result.
+func WithDryRunResult(result *DryRunResult) CallOption {
+	return WithDryRun(func(dryRunResult *DryRunResult) error {
+		*result = *dryRunResult
+		return nil
+	})
+}
+
+func newDryRunResult(httpRequest *http.Request) (*DryRunResult, error) {
+	var body []byte
+	if httpRequest.Body != nil {
+		var err error
+		body, err = io.ReadAll(httpRequest.Body)
+		httpRequest.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRequestEncode, err)
+		}
+
+		httpRequest.Body = io.NopCloser(bytes.NewReader(body))
+		httpRequest.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	result := &DryRunResult{
+		Method:  httpRequest.Method,
+		URL:     httpRequest.URL.String(),
+		Header:  httpRequest.Header.Clone(),
+		Body:    body,
+		Request: httpRequest.WithContext(context.Background()),
+	}
+	if httpRequest.Host != httpRequest.URL.Host {
+		result.Host = httpRequest.Host
+	}
+
+	return result, nil
+}
+
+// Curl returns the request as a curl command line. Bodies that are not valid text, such
+// as compressed bodies, are piped to curl encoded in base64.
+func (r *DryRunResult) Curl() string {
+	var command strings.Builder
+
+	binary := len(r.Body) > 0 && (!utf8.Valid(r.Body) || bytes.IndexByte(r.Body, 0) >= 0)
+	if binary {
+		command.WriteString("echo " + base64.StdEncoding.EncodeToString(r.Body) + " | base64 -d | ")
+	}
+
+	// curl -X HEAD waits for a body that never comes
+	if r.Method == http.MethodHead {
+		command.WriteString("curl -I " + shellQuote(r.URL))
+	} else {
+		command.WriteString("curl -X " + shellQuote(r.Method) + " " + shellQuote(r.URL))
+	}
+
+	if r.Host != "" {
+		command.WriteString(" -H " + shellQuote("Host: "+r.Host))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(r.Header)) {
+		for _, value := range r.Header[key] {
+			command.WriteString(" -H " + shellQuote(key+": "+value))
+		}
+	}
+
+	switch {
+	case binary:
+		command.WriteString(" --data-binary @-")
+	case len(r.Body) > 0:
+		command.WriteString(" --data-binary " + shellQuote(string(r.Body)))
+	}
+
+	return command.String()
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
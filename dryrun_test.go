@@ -0,0 +1,101 @@
+package restclientgo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRestClient_DryRun(t *testing.T) {
+	var sent atomic.Int32
+	var sentBody atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		sentBody.Store(string(body))
+		sent.Add(1)
+	}))
+	defer server.Close()
+
+	restClient := New(server.URL).WithRequestModifier(func(req *http.Request) *http.Request {
+		req.Header.Set("Authorization", "Bearer token")
+		return req
+	})
+
+	result := DryRunResult{}
+	response := &EchoResponse{Body: "untouched"}
+	err := restClient.Put(context.Background(), &echoRequest{body: "it's"}, response,
+		WithTimeout(time.Minute), WithDryRunResult(&result))
+	if err != nil {
+		t.Fatalf("RestClient.Put() error = %v", err)
+	}
+
+	if sent.Load() != 0 || response.Body != "untouched" {
+		t.Errorf("RestClient.Put() sent %d requests, response %q", sent.Load(), response.Body)
+	}
+
+	wantCurl := "curl -X 'PUT' '" + server.URL + "/echo' -H 'Authorization: Bearer token'" +
+		" -H 'Content-Type: text/plain' --data-binary 'it'\\''s'"
+	if got := result.Curl(); got != wantCurl {
+		t.Errorf("DryRunResult.Curl() = %v, want %v", got, wantCurl)
+	}
+
+	// the built request can still be sent once the call has returned
+	httpResponse, err := http.DefaultClient.Do(result.Request)
+	if err != nil {
+		t.Fatalf("http.Client.Do() error = %v", err)
+	}
+	httpResponse.Body.Close()
+	if sentBody.Load() != "it's" || string(result.Body) != "it's" {
+		t.Errorf("DryRunResult body = %q, sent body %q", result.Body, sentBody.Load())
+	}
+
+	// client-level dry run, with a callback rejecting the call
+	errRejected := errors.New("rejected")
+	var method string
+	dryRunClient := New(server.URL).WithCallOptions(WithDryRun(func(result *DryRunResult) error {
+		method = result.Method
+		return errRejected
+	}))
+
+	err = dryRunClient.Delete(context.Background(), &echoRequest{}, &EchoResponse{})
+	if !errors.Is(err, errRejected) || method != http.MethodDelete || sent.Load() != 1 {
+		t.Errorf("RestClient.Delete() error = %v, method %v, sent %d", err, method, sent.Load())
+	}
+}
+
+func TestDryRunResult_Curl(t *testing.T) {
+	tests := []struct {
+		name   string
+		result DryRunResult
+		want   string
+	}{
+		{
+			name:   "head",
+			result: DryRunResult{Method: http.MethodHead, URL: "http://api.internal/items"},
+			want:   "curl -I 'http://api.internal/items'",
+		},
+		{
+			name: "binary body",
+			result: DryRunResult{
+				Method: http.MethodPost,
+				URL:    "http://api.internal/items",
+				Header: http.Header{"Content-Encoding": {EncodingGzip}},
+				Body:   []byte{0x1f, 0x8b, 0x00, 0xff},
+			},
+			want: "echo H4sA/w== | base64 -d | curl -X 'POST' 'http://api.internal/items'" +
+				" -H 'Content-Encoding: gzip' --data-binary @-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.Curl(); got != tt.want {
+				t.Errorf("DryRunResult.Curl() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
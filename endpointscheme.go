@@ -0,0 +1,147 @@
+package restclientgo
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// EndpointScheme resolves an endpoint with a custom URL scheme into the HTTP endpoint
+// used to build the request URLs and the transport delivering the requests.
+type EndpointScheme func(endpoint *url.URL) (httpEndpoint string, transport http.RoundTripper, err error)
+
+var (
+	endpointSchemesMu sync.RWMutex
+	endpointSchemes   = map[string]EndpointScheme{
+		"unix": unixEndpointScheme,
+	}
+)
+
+// RegisterEndpointScheme registers the handler of the endpoints with the given URL scheme.
+// The "unix" scheme is registered by default.
+func RegisterEndpointScheme(scheme string, endpointScheme EndpointScheme) {
+	endpointSchemesMu.Lock()
+	defer endpointSchemesMu.Unlock()
+
+	endpointSchemes[strings.ToLower(scheme)] = endpointScheme
+}
+
+// resolveEndpoint sets the endpoint of the client, resolving registered schemes.
+func (r *RestClient) resolveEndpoint(endpoint string) {
+	defer r.applyEndpointTransport()
+
+	r.endpoint = endpoint
+	r.rawEndpoint = ""
+	r.endpointErr = nil
+	r.endpointTransport = nil
+
+	scheme, _, found := strings.Cut(endpoint, "://")
+	if !found {
+		return
+	}
+
+	endpointSchemesMu.RLock()
+	endpointScheme, registered := endpointSchemes[strings.ToLower(scheme)]
+	endpointSchemesMu.RUnlock()
+	if !registered {
+		return
+	}
+
+	r.rawEndpoint = endpoint
+
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		r.endpointErr = fmt.Errorf("%w: %w", ErrEndpoint, err)
+		return
+	}
+
+	httpEndpoint, transport, err := endpointScheme(endpointURL)
+	if err != nil {
+		r.endpointErr = fmt.Errorf("%w: %w", ErrEndpoint, err)
+		return
+	}
+
+	r.endpoint = httpEndpoint
+	r.endpointTransport = transport
+}
+
+// applyEndpointTransport builds the http client delivering the requests: the client set
+// by the user, with the transport of the endpoint scheme if any. A user transport that is
+// an *http.Transport is cloned with the dialer and proxy of the scheme transport; any
+// other user transport, such as a mock or a recorder, cannot deliver the requests of the
+// scheme and makes the calls fail with ErrEndpoint.
+func (r *RestClient) applyEndpointTransport() {
+	r.schemeClient = nil
+	r.schemeClientErr = nil
+
+	if r.endpointTransport == nil {
+		return
+	}
+
+	httpClient := *r.httpClient
+	switch transport := r.httpClient.Transport.(type) {
+	case nil:
+		httpClient.Transport = r.endpointTransport
+	case *http.Transport:
+		schemeTransport, ok := r.endpointTransport.(*http.Transport)
+		if !ok {
+			r.schemeClientErr = fmt.Errorf("%w: the endpoint scheme transport %T cannot be combined with %T",
+				ErrEndpoint, r.endpointTransport, transport)
+			return
+		}
+
+		combined := transport.Clone()
+		combined.Proxy = schemeTransport.Proxy
+		combined.DialContext = schemeTransport.DialContext
+		combined.DialTLSContext = schemeTransport.DialTLSContext
+		httpClient.Transport = combined
+	default:
+		r.schemeClientErr = fmt.Errorf("%w: the endpoint scheme cannot deliver requests through transport %T",
+			ErrEndpoint, transport)
+		return
+	}
+
+	r.schemeClient = &httpClient
+}
+
+// client returns the http client delivering the requests.
+func (r *RestClient) client() (*http.Client, error) {
+	if r.schemeClientErr != nil {
+		return nil, r.schemeClientErr
+	}
+
+	if r.schemeClient != nil {
+		return r.schemeClient, nil
+	}
+
+	return r.httpClient, nil
+}
+
+// unixEndpointScheme resolves unix:///path/to/socket endpoints, optionally followed by
+// an HTTP path prefix separated by a colon, as in unix:///var/run/app.sock:/v1.
+func unixEndpointScheme(endpoint *url.URL) (string, http.RoundTripper, error) {
+	socketPath, prefix, _ := strings.Cut(endpoint.Host+endpoint.Path, ":")
+	if socketPath == "" {
+		return "", nil, fmt.Errorf("missing socket path in %s", endpoint)
+	}
+
+	transport := &http.Transport{}
+	if defaultTransport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = defaultTransport.Clone()
+	}
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", socketPath)
+	}
+
+	httpEndpoint := "http://localhost" + prefix
+	if endpoint.RawQuery != "" {
+		httpEndpoint += "?" + endpoint.RawQuery
+	}
+
+	return httpEndpoint, transport, nil
+}
@@ -0,0 +1,144 @@
+package restclientgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRestClient_UnixEndpoint(t *testing.T) {
+	// unix socket paths are limited in length, so t.TempDir can't be used
+	dir, err := os.MkdirTemp("", "rcg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	socketPath := filepath.Join(dir, "api.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":1,"userId":1,"title":%q,"completed":false}`, req.URL.Path)
+	})}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		endpoint   string
+		httpClient *http.Client
+		wantTitle  string
+		wantErr    error
+	}{
+		{
+			name:      "socket",
+			endpoint:  "unix://" + socketPath,
+			wantTitle: "/todos/1",
+		},
+		{
+			name:      "socket with path prefix",
+			endpoint:  "unix://" + socketPath + ":/v1.41",
+			wantTitle: "/v1.41/todos/1",
+		},
+		{
+			name:       "socket with http client set after the endpoint",
+			endpoint:   "unix://" + socketPath,
+			httpClient: &http.Client{},
+			wantTitle:  "/todos/1",
+		},
+		{
+			name:       "socket with user http transport",
+			endpoint:   "unix://" + socketPath,
+			httpClient: &http.Client{Transport: &http.Transport{DisableKeepAlives: true}},
+			wantTitle:  "/todos/1",
+		},
+		{
+			name:       "socket with user round tripper",
+			endpoint:   "unix://" + socketPath,
+			httpClient: &http.Client{Transport: NewHandlerTransport(http.NotFoundHandler())},
+			wantErr:    ErrEndpoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restClient := New(tt.endpoint)
+			if tt.httpClient != nil {
+				restClient.SetHTTPClient(tt.httpClient)
+			}
+
+			response := &TodoResponse{}
+			err := restClient.Get(context.Background(), &todoRequest{ID: "1"}, response)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RestClient.Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if response.Title != tt.wantTitle {
+				t.Errorf("RestClient.Get() path = %s, want %s", response.Title, tt.wantTitle)
+			}
+
+			if restClient.Endpoint() != tt.endpoint {
+				t.Errorf("RestClient.Endpoint() = %s, want %s", restClient.Endpoint(), tt.endpoint)
+			}
+		})
+	}
+}
+
+func TestRestClient_UnixEndpointReset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":1,"userId":1,"title":%q,"completed":false}`, req.URL.Path)
+	}))
+	defer server.Close()
+
+	restClient := New("unix:///nonexistent/api.sock")
+	restClient.SetEndpoint(server.URL)
+
+	response := &TodoResponse{}
+	if err := restClient.Get(context.Background(), &todoRequest{ID: "1"}, response); err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	if response.Title != "/todos/1" {
+		t.Errorf("RestClient.Get() path = %s, want %s", response.Title, "/todos/1")
+	}
+}
+
+func TestRegisterEndpointScheme(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":1,"userId":1,"title":%q,"completed":false}`, req.Host+req.URL.Path)
+	})
+
+	errUnknownService := errors.New("unknown service")
+	RegisterEndpointScheme("inproc", func(endpoint *url.URL) (string, http.RoundTripper, error) {
+		if endpoint.Host != "todos" {
+			return "", nil, errUnknownService
+		}
+		return "http://todos.internal/api", NewHandlerTransport(handler), nil
+	})
+
+	response := &TodoResponse{}
+	if err := New("inproc://todos").Get(context.Background(), &todoRequest{ID: "1"}, response); err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	if response.Title != "todos.internal/api/todos/1" {
+		t.Errorf("RestClient.Get() = %s, want %s", response.Title, "todos.internal/api/todos/1")
+	}
+
+	err := New("inproc://users").Get(context.Background(), &todoRequest{ID: "1"}, &TodoResponse{})
+	if !errors.Is(err, ErrEndpoint) || !errors.Is(err, errUnknownService) {
+		t.Errorf("RestClient.Get() error = %v, want %v", err, errUnknownService)
+	}
+}
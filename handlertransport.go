@@ -0,0 +1,210 @@
+package restclientgo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// HandlerTransport is an http.RoundTripper dispatching requests to an http.Handler
+// in the same process, without opening sockets. Responses are streamed: the body
+// is read while the handler writes it.
+type HandlerTransport struct {
+	handler http.Handler
+}
+
+// NewHandlerTransport creates a new HandlerTransport dispatching requests to handler.
+func NewHandlerTransport(handler http.Handler) *HandlerTransport {
+	return &HandlerTransport{handler: handler}
+}
+
+// RoundTrip serves the request with the handler, returning as soon as the handler
+// writes the response header.
+func (h *HandlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+
+	serverRequest := req.Clone(ctx)
+	serverRequest.RequestURI = req.URL.RequestURI()
+	serverRequest.RemoteAddr = "127.0.0.1:0"
+	if serverRequest.Body == nil {
+		serverRequest.Body = http.NoBody
+	}
+	if serverRequest.Host == "" {
+		serverRequest.Host = req.URL.Host
+	}
+
+	reader, writer := io.Pipe()
+	responseWriter := &handlerResponseWriter{
+		ctx:    ctx,
+		header: make(http.Header),
+		writer: writer,
+		ready:  make(chan struct{}),
+		response: &http.Response{
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Body:       &handlerResponseBody{reader: reader, cancel: cancel},
+			Request:    req,
+		},
+	}
+
+	go func() {
+		<-ctx.Done()
+		writer.CloseWithError(ctx.Err())
+	}()
+
+	go responseWriter.serve(h.handler, serverRequest)
+
+	select {
+	case <-responseWriter.ready:
+	case <-ctx.Done():
+		cancel()
+		closeBody(req.Body)
+		return nil, ctx.Err()
+	}
+
+	if responseWriter.err != nil {
+		cancel()
+		return nil, responseWriter.err
+	}
+
+	return responseWriter.response, nil
+}
+
+// handlerResponseWriter is the http.ResponseWriter given to the handler, writing
+// the body to a pipe read by the client.
+type handlerResponseWriter struct {
+	ctx         context.Context
+	mu          sync.Mutex
+	header      http.Header
+	writer      *io.PipeWriter
+	ready       chan struct{}
+	wroteHeader bool
+	response    *http.Response
+	err         error
+}
+
+func (w *handlerResponseWriter) serve(handler http.Handler, req *http.Request) {
+	defer func() {
+		// the request body is closed once the handler returns, as by http.Server
+		_ = req.Body.Close()
+
+		if recovered := recover(); recovered != nil {
+			w.fail(fmt.Errorf("%w: handler panic: %v", ErrHTTPRequest, recovered))
+			return
+		}
+		w.finish()
+	}()
+
+	handler.ServeHTTP(w, req)
+}
+
+func (w *handlerResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *handlerResponseWriter) WriteHeader(statusCode int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.writeHeader(statusCode)
+}
+
+func (w *handlerResponseWriter) writeHeader(statusCode int) {
+	if w.wroteHeader || (statusCode >= 100 && statusCode < 200) {
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.header.Clone()
+	contentLength := int64(-1)
+	if value, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil {
+		contentLength = value
+	}
+
+	w.response.Status = fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode))
+	w.response.StatusCode = statusCode
+	w.response.Header = header
+	w.response.ContentLength = contentLength
+	w.response.Trailer = make(http.Header)
+	for _, key := range header.Values("Trailer") {
+		for _, name := range strings.Split(key, ",") {
+			w.response.Trailer[http.CanonicalHeaderKey(strings.TrimSpace(name))] = nil
+		}
+	}
+
+	close(w.ready)
+}
+
+func (w *handlerResponseWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	if !w.wroteHeader {
+		if w.header.Get("Content-Type") == "" {
+			w.header.Set("Content-Type", http.DetectContentType(p))
+		}
+		w.writeHeader(http.StatusOK)
+	}
+	w.mu.Unlock()
+
+	return w.writer.Write(p)
+}
+
+// Flush sends the response header if not sent yet. Written data is always
+// delivered to the client as it is read.
+func (w *handlerResponseWriter) Flush() {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (w *handlerResponseWriter) finish() {
+	w.mu.Lock()
+	w.writeHeader(http.StatusOK)
+
+	for key := range w.response.Trailer {
+		w.response.Trailer[key] = w.header.Values(key)
+	}
+	for key, values := range w.header {
+		if name, found := strings.CutPrefix(key, http.TrailerPrefix); found {
+			w.response.Trailer[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	w.mu.Unlock()
+
+	// a handler returning because the request was canceled must not end the body cleanly
+	if err := w.ctx.Err(); err != nil {
+		w.writer.CloseWithError(err)
+		return
+	}
+
+	w.writer.Close()
+}
+
+func (w *handlerResponseWriter) fail(err error) {
+	w.mu.Lock()
+	if !w.wroteHeader {
+		w.err = err
+		w.wroteHeader = true
+		close(w.ready)
+	}
+	w.mu.Unlock()
+
+	w.writer.CloseWithError(err)
+}
+
+// handlerResponseBody cancels the handler context when closed.
+type handlerResponseBody struct {
+	reader *io.PipeReader
+	cancel context.CancelFunc
+}
+
+func (b *handlerResponseBody) Read(p []byte) (int, error) {
+	return b.reader.Read(p)
+}
+
+func (b *handlerResponseBody) Close() error {
+	b.cancel()
+	return b.reader.Close()
+}
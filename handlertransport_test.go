@@ -0,0 +1,187 @@
+package restclientgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestHandlerTransport(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("GET /todos/{id}", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":%s,"userId":1,"title":"in process","completed":true}`, req.PathValue("id"))
+	})
+
+	restClient := New("http://todos.internal").WithHTTPClient(&http.Client{Transport: NewHandlerTransport(handler)})
+
+	response := &TodoResponse{}
+	if err := restClient.Get(context.Background(), &todoRequest{ID: "3"}, response); err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	want := TodoResponse{HTTPStatusCode: http.StatusOK, ID: 3, UserID: 1, Title: "in process", Completed: true}
+	if *response != want {
+		t.Errorf("RestClient.Get() = %+v, want %+v", *response, want)
+	}
+
+	response = &TodoResponse{}
+	if err := restClient.Get(context.Background(), &todoRequest{ID: "3/comments"}, response); err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	if response.HTTPStatusCode != http.StatusNotFound {
+		t.Errorf("RestClient.Get() status code = %d, want %d", response.HTTPStatusCode, http.StatusNotFound)
+	}
+}
+
+func TestHandlerTransport_Stream(t *testing.T) {
+	received := make(chan string)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		for i := range 3 {
+			fmt.Fprintf(w, "line %d\n", i)
+			w.(http.Flusher).Flush()
+
+			// the client must receive the line before the next one is written
+			select {
+			case <-received:
+			case <-time.After(time.Second):
+				http.Error(w, "line not received", http.StatusInternalServerError)
+				return
+			}
+		}
+	})
+
+	restClient := New("http://stream.internal").WithHTTPClient(&http.Client{Transport: NewHandlerTransport(handler)})
+
+	response := &StreamingEchoResponse{}
+	err := restClient.Get(context.Background(), &echoRequest{}, &streamNotifier{StreamingEchoResponse: response, received: received})
+	if err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	if len(response.Chunks) != 3 {
+		t.Errorf("RestClient.Get() chunks = %v, want 3 lines", response.Chunks)
+	}
+}
+
+func TestHandlerTransport_Cancel(t *testing.T) {
+	handlerDone := make(chan error, 1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.(http.Flusher).Flush()
+
+		<-req.Context().Done()
+		handlerDone <- req.Context().Err()
+	})
+
+	restClient := New("http://cancel.internal").WithHTTPClient(&http.Client{Transport: NewHandlerTransport(handler)})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := restClient.Get(ctx, &echoRequest{}, &EchoResponse{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RestClient.Get() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	select {
+	case err := <-handlerDone:
+		if err == nil {
+			t.Errorf("handler context not canceled")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("handler not canceled")
+	}
+}
+
+func TestHandlerTransport_CancelAfterBody(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("partial"))
+
+		// the handler returns right after the cancellation
+		<-req.Context().Done()
+	})
+
+	transport := NewHandlerTransport(handler)
+
+	// the handler and the cancellation of the pipe race, so repeat to catch a clean EOF
+	for range 100 {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://cancel.internal/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		response, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("HandlerTransport.RoundTrip() error = %v", err)
+		}
+
+		head := make([]byte, len("partial"))
+		if _, err := io.ReadFull(response.Body, head); err != nil {
+			t.Fatalf("Body.Read() error = %v", err)
+		}
+
+		cancel()
+
+		_, err = io.ReadAll(response.Body)
+		response.Body.Close()
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Body.Read() error = %v, want %v", err, context.Canceled)
+		}
+	}
+}
+
+func TestHandlerTransport_RequestBodyClosed(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// the handler stops reading the body early
+		_, _ = io.ReadFull(req.Body, make([]byte, 1024))
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	var open atomic.Int32
+	fsys := closeTrackingFS{MapFS: fstest.MapFS{"data.txt": {Data: []byte(strings.Repeat("data", 64*1024))}}, open: &open}
+
+	restClient := New("http://upload.internal").WithHTTPClient(&http.Client{Transport: NewHandlerTransport(handler)})
+
+	request := NewMultipartRequest("/upload").AddFileFS("file", fsys, "data.txt")
+	response := &EchoResponse{}
+	if err := restClient.Post(context.Background(), request, response); err != nil {
+		t.Fatalf("RestClient.Post() error = %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for open.Load() != 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if open.Load() != 0 {
+		t.Errorf("open files = %d, want 0", open.Load())
+	}
+}
+
+type streamNotifier struct {
+	*StreamingEchoResponse
+	received chan string
+}
+
+func (s *streamNotifier) StreamCallback() StreamCallback {
+	callback := s.StreamingEchoResponse.StreamCallback()
+	return func(line []byte) error {
+		err := callback(line)
+		s.received <- string(line)
+		return err
+	}
+}
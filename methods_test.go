@@ -0,0 +1,139 @@
+package restclientgo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type methodResponse struct {
+	statusCode int
+	headers    Headers
+	decoded    bool
+}
+
+func (r *methodResponse) Decode(body io.Reader) error {
+	r.decoded = true
+	_, err := io.ReadAll(body)
+	return err
+}
+func (r *methodResponse) SetBody(body io.Reader) error { return r.Decode(body) }
+func (r *methodResponse) AcceptContentType() string    { return "application/xml" }
+func (r *methodResponse) SetStatusCode(code int) error {
+	r.statusCode = code
+	return nil
+}
+func (r *methodResponse) SetHeaders(headers Headers) error {
+	r.headers = headers
+	return nil
+}
+
+func TestRestClient_Methods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Method", req.Method)
+		if req.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// a content type the response does not accept
+		w.Header().Set("Content-Type", "text/html")
+		w.Header().Set("Allow", "GET, HEAD, OPTIONS, PROPFIND")
+		w.WriteHeader(http.StatusMultiStatus)
+	}))
+	defer server.Close()
+
+	restClient := New(server.URL)
+
+	tests := []struct {
+		name        string
+		call        func(context.Context, Request, Decoder, ...CallOption) error
+		method      string
+		path        string
+		wantStatus  int
+		wantDecoded bool
+		wantErr     error
+	}{
+		{
+			name:       "head",
+			call:       restClient.Head,
+			method:     http.MethodHead,
+			path:       "found",
+			wantStatus: http.StatusMultiStatus,
+		},
+		{
+			name:       "head missing",
+			call:       restClient.Head,
+			method:     http.MethodHead,
+			path:       "missing",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:    "options",
+			call:    restClient.Options,
+			method:  http.MethodOptions,
+			path:    "found",
+			wantErr: ErrNoContentType,
+		},
+		{
+			name: "propfind",
+			call: func(ctx context.Context, request Request, response Decoder, opts ...CallOption) error {
+				return restClient.Do(ctx, "PROPFIND", request, response, opts...)
+			},
+			method:      "PROPFIND",
+			path:        "missing",
+			wantStatus:  http.StatusNotFound,
+			wantDecoded: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &methodResponse{}
+			err := tt.call(context.Background(), &optionsRequest{path: tt.path}, response)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RestClient.%s() error = %v, wantErr %v", tt.method, err, tt.wantErr)
+			}
+
+			if got := response.headers["X-Method"]; len(got) != 1 || got[0] != tt.method {
+				t.Errorf("RestClient.%s() method = %v, want %v", tt.method, got, tt.method)
+			}
+
+			if tt.wantErr == nil && (response.statusCode != tt.wantStatus || response.decoded != tt.wantDecoded) {
+				t.Errorf("RestClient.%s() status = %d, decoded %v", tt.method, response.statusCode, response.decoded)
+			}
+		})
+	}
+
+	err := restClient.Do(context.Background(), "BAD METHOD", &optionsRequest{path: "found"}, &methodResponse{})
+	if !errors.Is(err, ErrHTTPRequest) {
+		t.Errorf("RestClient.Do() error = %v, want %v", err, ErrHTTPRequest)
+	}
+}
+
+// requestlessTransport answers without setting the request of the response, as some
+// transports do.
+type requestlessTransport struct{}
+
+func (requestlessTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"X-Method": {http.MethodHead}},
+		Body:       http.NoBody,
+	}, nil
+}
+
+func TestRestClient_HeadWithoutResponseRequest(t *testing.T) {
+	restClient := New("http://head.internal").WithHTTPClient(&http.Client{Transport: requestlessTransport{}})
+
+	response := &methodResponse{}
+	if err := restClient.Head(context.Background(), &optionsRequest{path: "found"}, response); err != nil {
+		t.Fatalf("RestClient.Head() error = %v", err)
+	}
+
+	if response.statusCode != http.StatusOK || response.decoded {
+		t.Errorf("RestClient.Head() status = %d, decoded %v", response.statusCode, response.decoded)
+	}
+}
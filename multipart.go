@@ -0,0 +1,219 @@
+package restclientgo
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// MultipartRequest is a Request encoding fields and files as multipart/form-data.
+// The body is streamed while it is sent, so files are never fully buffered in memory.
+type MultipartRequest struct {
+	path     string
+	boundary string
+	parts    []multipartPart
+}
+
+type multipartPart struct {
+	header textproto.MIMEHeader
+	open   func() (io.ReadCloser, error)
+	size   func() int64
+}
+
+// NewMultipartRequest creates a new MultipartRequest for the given path.
+func NewMultipartRequest(path string) *MultipartRequest {
+	return &MultipartRequest{
+		path:     path,
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+	}
+}
+
+// AddField adds a form field.
+func (m *MultipartRequest) AddField(name, value string) *MultipartRequest {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"`, quoteEscaper.Replace(name)))
+
+	return m.AddPart(header, strings.NewReader(value), int64(len(value)))
+}
+
+// AddFile adds a file read from reader. size is the number of bytes of the file,
+// or -1 if unknown. The reader is consumed by the first Encode call.
+func (m *MultipartRequest) AddFile(fieldName, fileName string, reader io.Reader, size int64) *MultipartRequest {
+	return m.AddPart(fileHeader(fieldName, fileName), reader, size)
+}
+
+// AddFileFS adds the file name read from fsys. The file is opened each time the
+// request is encoded.
+func (m *MultipartRequest) AddFileFS(fieldName string, fsys fs.FS, name string) *MultipartRequest {
+	m.parts = append(m.parts, multipartPart{
+		header: fileHeader(fieldName, path.Base(name)),
+		open: func() (io.ReadCloser, error) {
+			return fsys.Open(name)
+		},
+		size: func() int64 {
+			info, err := fs.Stat(fsys, name)
+			if err != nil || !info.Mode().IsRegular() {
+				return -1
+			}
+			return info.Size()
+		},
+	})
+
+	return m
+}
+
+// AddPart adds a part with custom headers. size is the number of bytes of the part
+// body, or -1 if unknown. The reader is consumed by the first Encode call.
+func (m *MultipartRequest) AddPart(header textproto.MIMEHeader, reader io.Reader, size int64) *MultipartRequest {
+	m.parts = append(m.parts, multipartPart{
+		header: header,
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(reader), nil
+		},
+		size: func() int64 {
+			return size
+		},
+	})
+
+	return m
+}
+
+// Path returns the path of the request.
+func (m *MultipartRequest) Path() (string, error) {
+	return m.path, nil
+}
+
+// Encode returns the multipart body. Parts are written through a pipe as the body is read.
+func (m *MultipartRequest) Encode() (io.Reader, error) {
+	reader, writer := io.Pipe()
+
+	return &pipeBody{
+		reader: reader,
+		start: func() {
+			writer.CloseWithError(m.writeParts(writer))
+		},
+	}, nil
+}
+
+// ContentType returns the multipart/form-data content type including the boundary.
+func (m *MultipartRequest) ContentType() string {
+	return mime.FormatMediaType("multipart/form-data", map[string]string{"boundary": m.boundary})
+}
+
+// ContentLength returns the size of the encoded body, or -1 if the size of any part is unknown.
+func (m *MultipartRequest) ContentLength() int64 {
+	counter := &countingWriter{}
+	writer := multipart.NewWriter(counter)
+	_ = writer.SetBoundary(m.boundary)
+
+	var length int64
+	for _, part := range m.parts {
+		size := part.size()
+		if size < 0 {
+			return -1
+		}
+		length += size
+
+		_, _ = writer.CreatePart(part.header)
+	}
+	_ = writer.Close()
+
+	return length + counter.n
+}
+
+func (m *MultipartRequest) writeParts(w io.Writer) error {
+	writer := multipart.NewWriter(w)
+
+	err := writer.SetBoundary(m.boundary)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range m.parts {
+		err = writePart(writer, part)
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func writePart(writer *multipart.Writer, part multipartPart) error {
+	partWriter, err := writer.CreatePart(part.header)
+	if err != nil {
+		return err
+	}
+
+	reader, err := part.open()
+	if err != nil {
+		return err
+	}
+
+	defer reader.Close()
+
+	_, err = io.Copy(partWriter, reader)
+	return err
+}
+
+func fileHeader(fieldName, fileName string) textproto.MIMEHeader {
+	contentType := mime.TypeByExtension(filepath.Ext(fileName))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set(
+		"Content-Disposition",
+		fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(fieldName), quoteEscaper.Replace(fileName)),
+	)
+	header.Set("Content-Type", contentType)
+
+	return header
+}
+
+// pipeBody starts the writer of the pipe on the first Read, so that nothing is
+// left running if the body is never sent.
+type pipeBody struct {
+	once   sync.Once
+	reader *io.PipeReader
+	start  func()
+	// abort, if set, is called on Close if the writer has not been started.
+	abort func()
+}
+
+func (b *pipeBody) Read(p []byte) (int, error) {
+	b.once.Do(func() {
+		go b.start()
+	})
+
+	return b.reader.Read(p)
+}
+
+func (b *pipeBody) Close() error {
+	b.once.Do(func() {
+		if b.abort != nil {
+			b.abort()
+		}
+	})
+
+	return b.reader.Close()
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
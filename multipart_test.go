@@ -0,0 +1,116 @@
+package restclientgo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+type UploadResponse struct {
+	HTTPStatusCode int
+	Body           string
+}
+
+func (r *UploadResponse) Decode(body io.Reader) error { return r.SetBody(body) }
+func (r *UploadResponse) SetBody(body io.Reader) error {
+	b, err := io.ReadAll(body)
+	r.Body = string(b)
+	return err
+}
+func (r *UploadResponse) AcceptContentType() string { return "" }
+func (r *UploadResponse) SetStatusCode(code int) error {
+	r.HTTPStatusCode = code
+	return nil
+}
+func (r *UploadResponse) SetHeaders(headers Headers) error {
+	_ = headers
+	return nil
+}
+
+func TestMultipartRequest(t *testing.T) {
+	fsys := fstest.MapFS{
+		"docs/report.txt": &fstest.MapFile{Data: []byte("quarterly report")},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		if req.ContentLength != int64(len(body)) && req.ContentLength != -1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := req.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		result := req.FormValue("title")
+		for _, name := range []string{"attachment", "report"} {
+			file, header, err := req.FormFile(name)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			content, _ := io.ReadAll(file)
+			result += "|" + header.Filename + ":" + string(content)
+		}
+		result += "|" + req.MultipartForm.File["report"][0].Header.Get("Content-Type")
+
+		if req.ContentLength == -1 {
+			result += "|chunked"
+		}
+
+		_, _ = w.Write([]byte(result))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		request *MultipartRequest
+		want    string
+	}{
+		{
+			name: "known size",
+			request: NewMultipartRequest("/upload").
+				AddField("title", "hello").
+				AddFile("attachment", "a.bin", strings.NewReader("binary data"), 11).
+				AddFileFS("report", fsys, "docs/report.txt"),
+			want: "hello|a.bin:binary data|report.txt:quarterly report|text/plain; charset=utf-8",
+		},
+		{
+			name: "unknown size",
+			request: NewMultipartRequest("/upload").
+				AddField("title", "hello").
+				AddFile("attachment", "a.bin", strings.NewReader("binary data"), -1).
+				AddFileFS("report", fsys, "docs/report.txt"),
+			want: "hello|a.bin:binary data|report.txt:quarterly report|text/plain; charset=utf-8|chunked",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &UploadResponse{}
+			if err := New(server.URL).Post(context.Background(), tt.request, response); err != nil {
+				t.Fatalf("RestClient.Post() error = %v", err)
+			}
+
+			if response.HTTPStatusCode != http.StatusOK {
+				t.Fatalf("RestClient.Post() status code = %d, want %d", response.HTTPStatusCode, http.StatusOK)
+			}
+
+			if response.Body != tt.want {
+				t.Errorf("RestClient.Post() = %s, want %s", response.Body, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package restclientgo
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// QueryEscaping is the escaping of spaces in encoded query strings.
+type QueryEscaping int
+
+const (
+	// QueryEscapingPlus encodes spaces as "+", like url.Values.
+	QueryEscapingPlus QueryEscaping = iota
+	// QueryEscapingPercent encodes spaces as "%20", as required by RFC 3986 based
+	// signatures.
+	QueryEscapingPercent
+)
+
+// OrderedURLValues is a query string that keeps the insertion order of its values.
+// Duplicate keys keep their original position. The string returned by Encode is sent
+// as it is, so request modifiers signing req.URL.RawQuery sign the exact query sent.
+type OrderedURLValues struct {
+	pairs    []queryPair
+	escaping QueryEscaping
+}
+
+// NewOrderedURLValues creates a new OrderedURLValues.
+func NewOrderedURLValues() *OrderedURLValues {
+	return &OrderedURLValues{}
+}
+
+// WithEscaping sets the escaping of spaces. The default is QueryEscapingPlus.
+func (p *OrderedURLValues) WithEscaping(escaping QueryEscaping) *OrderedURLValues {
+	p.escaping = escaping
+	return p
+}
+
+// Add adds a string value to the OrderedURLValues. Empty strings are omitted, use the
+// package level Add to choose a ZeroPolicy.
+func (p *OrderedURLValues) Add(key string, value *string) {
+	if value != nil && *value != "" {
+		p.AddValue(key, *value)
+	}
+}
+
+// AddInt adds an int value to the OrderedURLValues.
+func (p *OrderedURLValues) AddInt(key string, value *int) {
+	if value != nil {
+		p.AddValue(key, fmt.Sprintf("%d", *value))
+	}
+}
+
+// AddBool adds a bool value to the OrderedURLValues.
+func (p *OrderedURLValues) AddBool(key string, value *bool) {
+	if value != nil {
+		p.AddValue(key, fmt.Sprintf("%t", *value))
+	}
+}
+
+// AddBoolAsInt adds a bool value to the OrderedURLValues as an int (0=false, 1=true).
+func (p *OrderedURLValues) AddBoolAsInt(key string, value *bool) {
+	if value != nil {
+		if *value {
+			p.AddValue(key, "1")
+		} else {
+			p.AddValue(key, "0")
+		}
+	}
+}
+
+// AddFloat adds a float value to the OrderedURLValues, formatted without losing precision.
+func (p *OrderedURLValues) AddFloat(key string, value *float64) {
+	if value != nil {
+		p.AddValue(key, strconv.FormatFloat(*value, 'f', -1, 64))
+	}
+}
+
+// AddValue adds a value to the OrderedURLValues as it is, even if empty.
+func (p *OrderedURLValues) AddValue(key string, value string) {
+	p.pairs = append(p.pairs, queryPair{key: key, value: value})
+}
+
+// AddArray adds the values of an array with the given style.
+func (p *OrderedURLValues) AddArray(key string, values []string, style QueryStyle, explode bool) {
+	addQueryArray(p.AddValue, key, values, style, explode)
+}
+
+// AddObject adds the properties of an object, sorted by name, with the given style.
+func (p *OrderedURLValues) AddObject(key string, object map[string]string, style QueryStyle, explode bool) {
+	addQueryObject(p.AddValue, key, sortedQueryPairs(object), style, explode)
+}
+
+// Get returns the first value associated with key, or "" if there is none.
+func (p *OrderedURLValues) Get(key string) string {
+	for _, pair := range p.pairs {
+		if pair.key == key {
+			return pair.value
+		}
+	}
+
+	return ""
+}
+
+// Del deletes the values associated with key.
+func (p *OrderedURLValues) Del(key string) {
+	pairs := p.pairs[:0]
+	for _, pair := range p.pairs {
+		if pair.key != key {
+			pairs = append(pairs, pair)
+		}
+	}
+	p.pairs = pairs
+}
+
+// Encode encodes the OrderedURLValues into "URL encoded" form ("foo=quux&bar=baz"),
+// in insertion order.
+func (p *OrderedURLValues) Encode() string {
+	var encoded strings.Builder
+	for i, pair := range p.pairs {
+		if i > 0 {
+			encoded.WriteByte('&')
+		}
+		encoded.WriteString(p.escape(pair.key))
+		encoded.WriteByte('=')
+		encoded.WriteString(p.escape(pair.value))
+	}
+
+	return encoded.String()
+}
+
+func (p *OrderedURLValues) escape(s string) string {
+	escaped := url.QueryEscape(s)
+	if p.escaping == QueryEscapingPercent {
+		// QueryEscape encodes "+" as %2B, so any "+" left is a space
+		escaped = strings.ReplaceAll(escaped, "+", "%20")
+	}
+
+	return escaped
+}
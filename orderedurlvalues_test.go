@@ -0,0 +1,98 @@
+package restclientgo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOrderedURLValues_Encode(t *testing.T) {
+	limit := 10
+	query := "a b+c"
+
+	tests := []struct {
+		name     string
+		escaping QueryEscaping
+		want     string
+	}{
+		{
+			name:     "plus",
+			escaping: QueryEscapingPlus,
+			want:     "z=1&q=a+b%2Bc&limit=10&z=2&tag=x",
+		},
+		{
+			name:     "percent",
+			escaping: QueryEscapingPercent,
+			want:     "z=1&q=a%20b%2Bc&limit=10&z=2&tag=x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := NewOrderedURLValues().WithEscaping(tt.escaping)
+			values.AddValue("z", "1")
+			values.Add("q", &query)
+			values.AddInt("limit", &limit)
+			values.AddValue("z", "2")
+			values.AddValue("removed", "x")
+			values.AddArray("tag", []string{"x"}, QueryStyleForm, true)
+			values.Del("removed")
+
+			if got := values.Encode(); got != tt.want {
+				t.Errorf("OrderedURLValues.Encode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeOrderedQuery(t *testing.T) {
+	got, err := EncodeOrderedQuery(struct {
+		Timestamp int    `url:"timestamp"`
+		Nonce     string `url:"nonce"`
+		Action    string `url:"action"`
+	}{Timestamp: 1700000000, Nonce: "n", Action: "list"})
+	if err != nil {
+		t.Fatalf("EncodeOrderedQuery() error = %v", err)
+	}
+
+	want := "timestamp=1700000000&nonce=n&action=list"
+	if got.Encode() != want {
+		t.Errorf("EncodeOrderedQuery() = %v, want %v", got.Encode(), want)
+	}
+}
+
+type orderedQueryRequest struct {
+	values *OrderedURLValues
+}
+
+func (r *orderedQueryRequest) Path() (string, error)      { return "/search?" + r.values.Encode(), nil }
+func (r *orderedQueryRequest) Encode() (io.Reader, error) { return nil, nil }
+func (r *orderedQueryRequest) ContentType() string        { return "" }
+
+func TestOrderedURLValues_SentAsIs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = io.WriteString(w, req.URL.RawQuery)
+	}))
+	defer server.Close()
+
+	values := NewOrderedURLValues().WithEscaping(QueryEscapingPercent)
+	values.AddValue("z", "last one")
+	values.AddValue("a", "x/y")
+
+	var signed string
+	restClient := New(server.URL).WithRequestModifier(func(req *http.Request) *http.Request {
+		signed = req.URL.RawQuery
+		return req
+	})
+
+	response := &EchoResponse{}
+	if err := restClient.Get(context.Background(), &orderedQueryRequest{values: values}, response); err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	if signed != values.Encode() || response.Body != values.Encode() {
+		t.Errorf("signed query = %v, sent query = %v, want %v", signed, response.Body, values.Encode())
+	}
+}
@@ -0,0 +1,477 @@
+package restclientgo
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PageResponse is a Response holding a single page of a paginated listing.
+type PageResponse[T any] interface {
+	Decoder
+	// Items returns the items contained in the page.
+	Items() []T
+}
+
+// CursorResponse is implemented by page responses carrying the cursor of the next page.
+type CursorResponse interface {
+	// NextCursor returns the cursor of the next page, empty if there are no more pages.
+	NextCursor() string
+}
+
+// PageInfo describes the last fetched page.
+type PageInfo struct {
+	// Number is the 1-based number of the page.
+	Number int
+	// Path is the request path used to fetch the page.
+	Path string
+	// URL is the absolute URL used to fetch the page.
+	URL string
+	// ItemCount is the number of items contained in the page.
+	ItemCount int
+	// Headers are the HTTP response headers of the page.
+	Headers Headers
+	// Response is the decoded page response.
+	Response Decoder
+}
+
+// PaginationStrategy computes the request path of each page.
+type PaginationStrategy interface {
+	// NextPath returns the path of the page following last, given the path of the
+	// original request. last is nil when the first page is requested. ok is false
+	// when there are no more pages. The returned path can be an absolute URL
+	// sharing the client endpoint.
+	NextPath(path string, last *PageInfo) (next string, ok bool, err error)
+}
+
+// NewLinkPagination creates a strategy following the RFC 5988 Link header with rel="next".
+func NewLinkPagination() PaginationStrategy {
+	return &linkPagination{}
+}
+
+// NewCursorPagination creates a strategy sending the cursor returned by the
+// previous page as the param query parameter. Page responses must implement
+// CursorResponse.
+func NewCursorPagination(param string) PaginationStrategy {
+	return &cursorPagination{param: param}
+}
+
+// NewOffsetPagination creates a strategy sending offset and limit query parameters.
+// Iteration stops when a page holds less than limit items.
+func NewOffsetPagination(offsetParam, limitParam string, limit int) PaginationStrategy {
+	return &offsetPagination{offsetParam: offsetParam, limitParam: limitParam, limit: limit}
+}
+
+// NewPageNumberPagination creates a strategy sending a 1-based page number and,
+// if sizeParam is not empty, the page size. Iteration stops when a page is empty
+// or holds less than size items.
+func NewPageNumberPagination(pageParam, sizeParam string, size int) PaginationStrategy {
+	return &pageNumberPagination{pageParam: pageParam, sizeParam: sizeParam, size: size}
+}
+
+// Paginator iterates over the pages of a listing endpoint.
+type Paginator[T any] struct {
+	client      *RestClient
+	request     Request
+	strategy    PaginationStrategy
+	newResponse func() PageResponse[T]
+	maxPages    int
+	prefetch    int
+}
+
+// NewPaginator creates a new Paginator fetching the pages of request with GET.
+// newResponse is called to allocate the response of each page.
+func NewPaginator[T any](
+	client *RestClient,
+	request Request,
+	strategy PaginationStrategy,
+	newResponse func() PageResponse[T],
+) *Paginator[T] {
+	return &Paginator[T]{
+		client:      client,
+		request:     request,
+		strategy:    strategy,
+		newResponse: newResponse,
+	}
+}
+
+// WithMaxPages stops the iteration with ErrMaxPages if more than maxPages pages are available.
+func (p *Paginator[T]) WithMaxPages(maxPages int) *Paginator[T] {
+	p.maxPages = maxPages
+	return p
+}
+
+// WithPrefetch fetches up to prefetch pages in background while the current one is consumed.
+func (p *Paginator[T]) WithPrefetch(prefetch int) *Paginator[T] {
+	p.prefetch = prefetch
+	return p
+}
+
+// Pages returns an iterator over the pages. The iteration stops at the first error.
+func (p *Paginator[T]) Pages(ctx context.Context) iter.Seq2[PageResponse[T], error] {
+	return func(yield func(PageResponse[T], error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		if p.prefetch <= 0 {
+			p.fetch(ctx, yield)
+			return
+		}
+
+		type result struct {
+			page PageResponse[T]
+			err  error
+		}
+
+		var producer sync.WaitGroup
+		producer.Add(1)
+
+		results := make(chan result, p.prefetch)
+		go func() {
+			defer producer.Done()
+			defer close(results)
+			p.fetch(ctx, func(page PageResponse[T], err error) bool {
+				select {
+				case results <- result{page: page, err: err}:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			})
+		}()
+
+		for res := range results {
+			if !yield(res.page, res.err) {
+				// the producer must not outlive the iteration
+				cancel()
+				producer.Wait()
+				return
+			}
+		}
+	}
+}
+
+// Items returns an iterator over the items of all the pages. The iteration stops
+// at the first error.
+func (p *Paginator[T]) Items(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for page, err := range p.Pages(ctx) {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Items() {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p *Paginator[T]) fetch(ctx context.Context, emit func(PageResponse[T], error) bool) {
+	path, err := p.request.Path()
+	if err != nil {
+		emit(nil, fmt.Errorf("%w: %w", ErrRequestPath, err))
+		return
+	}
+
+	var last *PageInfo
+	for number := 1; ; number++ {
+		next, ok, err := p.strategy.NextPath(path, last)
+		if err != nil {
+			emit(nil, fmt.Errorf("%w: %w", ErrPagination, err))
+			return
+		}
+		if !ok {
+			return
+		}
+
+		if p.maxPages > 0 && number > p.maxPages {
+			emit(nil, ErrMaxPages)
+			return
+		}
+
+		if ctx.Err() != nil {
+			emit(nil, ctx.Err())
+			return
+		}
+
+		var page PageResponse[T]
+		page, last, err = p.fetchPage(ctx, number, next)
+		if err != nil {
+			emit(nil, err)
+			return
+		}
+
+		if !emit(page, nil) {
+			return
+		}
+	}
+}
+
+func (p *Paginator[T]) fetchPage(ctx context.Context, number int, next string) (PageResponse[T], *PageInfo, error) {
+	response := &pageResponseRecorder[T]{PageResponse: p.newResponse()}
+	err := p.client.do(ctx, http.MethodGet, &pageRequest{Request: p.request, path: next}, response)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if response.statusCode >= 400 {
+		return nil, nil, fmt.Errorf("%w: unexpected status code %d", ErrPagination, response.statusCode)
+	}
+
+	pageURL, err := p.client.requestURL(next)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return response.PageResponse, &PageInfo{
+		Number:    number,
+		Path:      next,
+		URL:       pageURL,
+		ItemCount: len(response.Items()),
+		Headers:   response.headers,
+		Response:  response.PageResponse,
+	}, nil
+}
+
+type pageRequest struct {
+	Request
+	path string
+}
+
+func (p *pageRequest) Path() (string, error) {
+	return p.path, nil
+}
+
+func (p *pageRequest) unwrapRequest() Request {
+	return p.Request
+}
+
+type pageResponseRecorder[T any] struct {
+	PageResponse[T]
+	statusCode int
+	headers    Headers
+}
+
+func (p *pageResponseRecorder[T]) SetStatusCode(code int) error {
+	p.statusCode = code
+	if statusCodeSetter, ok := p.PageResponse.(StatusCodeSetter); ok {
+		return statusCodeSetter.SetStatusCode(code)
+	}
+	return nil
+}
+
+func (p *pageResponseRecorder[T]) SetHeaders(headers Headers) error {
+	p.headers = headers
+	if headersSetter, ok := p.PageResponse.(HeadersSetter); ok {
+		return headersSetter.SetHeaders(headers)
+	}
+	return nil
+}
+
+func (p *pageResponseRecorder[T]) unwrapResponse() Decoder {
+	return p.PageResponse
+}
+
+type linkPagination struct{}
+
+func (l *linkPagination) NextPath(path string, last *PageInfo) (string, bool, error) {
+	if last == nil {
+		return path, true, nil
+	}
+
+	next := nextLink(last.Headers["Link"])
+	if next == "" {
+		return "", false, nil
+	}
+
+	base, err := url.Parse(last.URL)
+	if err != nil {
+		return "", false, err
+	}
+
+	target, err := base.Parse(next)
+	if err != nil {
+		return "", false, err
+	}
+
+	return target.String(), true, nil
+}
+
+// nextLink returns the target of the rel="next" link found in the Link header values.
+func nextLink(values []string) string {
+	for _, value := range values {
+		for value != "" {
+			// the target is scanned first, as it may contain commas
+			start := strings.IndexByte(value, '<')
+			if start < 0 {
+				break
+			}
+			end := strings.IndexByte(value[start:], '>')
+			if end < 0 {
+				break
+			}
+			end += start
+
+			target := value[start+1 : end]
+
+			var params string
+			params, value = splitLinkParams(value[end+1:])
+			if hasNextRel(params) {
+				return target
+			}
+		}
+	}
+
+	return ""
+}
+
+// splitLinkParams splits the parameters of a link-value from the following link-values
+// at the first comma outside quoted strings.
+func splitLinkParams(value string) (string, string) {
+	quoted := false
+	for i := range len(value) {
+		switch value[i] {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				return value[:i], value[i+1:]
+			}
+		}
+	}
+
+	return value, ""
+}
+
+func hasNextRel(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		key, rel, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+			continue
+		}
+
+		for _, r := range strings.Fields(strings.Trim(strings.TrimSpace(rel), `"`)) {
+			if strings.EqualFold(r, "next") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type cursorPagination struct {
+	param string
+}
+
+func (c *cursorPagination) NextPath(path string, last *PageInfo) (string, bool, error) {
+	if last == nil {
+		return path, true, nil
+	}
+
+	cursorResponse, ok := last.Response.(CursorResponse)
+	if !ok {
+		return "", false, fmt.Errorf("%T does not implement CursorResponse", last.Response)
+	}
+
+	cursor := cursorResponse.NextCursor()
+	if cursor == "" {
+		return "", false, nil
+	}
+
+	next, err := setQueryParam(path, c.param, cursor)
+	return next, err == nil, err
+}
+
+type offsetPagination struct {
+	offsetParam string
+	limitParam  string
+	limit       int
+}
+
+func (o *offsetPagination) NextPath(path string, last *PageInfo) (string, bool, error) {
+	offset := 0
+	if last != nil {
+		if last.ItemCount == 0 || last.ItemCount < o.limit {
+			return "", false, nil
+		}
+
+		lastOffset, err := queryParam(last.Path, o.offsetParam)
+		if err != nil {
+			return "", false, err
+		}
+
+		offset, err = strconv.Atoi(lastOffset)
+		if err != nil {
+			return "", false, err
+		}
+		offset += last.ItemCount
+	}
+
+	next, err := setQueryParam(path, o.offsetParam, strconv.Itoa(offset))
+	if err != nil {
+		return "", false, err
+	}
+
+	next, err = setQueryParam(next, o.limitParam, strconv.Itoa(o.limit))
+	return next, err == nil, err
+}
+
+type pageNumberPagination struct {
+	pageParam string
+	sizeParam string
+	size      int
+}
+
+func (p *pageNumberPagination) NextPath(path string, last *PageInfo) (string, bool, error) {
+	page := 1
+	if last != nil {
+		if last.ItemCount == 0 || (p.size > 0 && last.ItemCount < p.size) {
+			return "", false, nil
+		}
+		page = last.Number + 1
+	}
+
+	next, err := setQueryParam(path, p.pageParam, strconv.Itoa(page))
+	if err != nil || p.sizeParam == "" {
+		return next, err == nil, err
+	}
+
+	next, err = setQueryParam(next, p.sizeParam, strconv.Itoa(p.size))
+	return next, err == nil, err
+}
+
+// queryParam returns the key query parameter of path.
+func queryParam(path, key string) (string, error) {
+	_, rawQuery, _ := strings.Cut(path, "?")
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", err
+	}
+
+	return query.Get(key), nil
+}
+
+// setQueryParam sets the key query parameter of path to value.
+func setQueryParam(path, key, value string) (string, error) {
+	path, rawQuery, _ := strings.Cut(path, "?")
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", err
+	}
+	query.Set(key, value)
+
+	return path + "?" + query.Encode(), nil
+}
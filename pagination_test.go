@@ -0,0 +1,295 @@
+package restclientgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type listItemsRequest struct{}
+
+func (r *listItemsRequest) Path() (string, error)      { return "/items", nil }
+func (r *listItemsRequest) Encode() (io.Reader, error) { return nil, nil }
+func (r *listItemsRequest) ContentType() string        { return "" }
+
+type ListItemsResponse struct {
+	Data   []int  `json:"data"`
+	Cursor string `json:"cursor"`
+}
+
+func (r *ListItemsResponse) Decode(body io.Reader) error  { return json.NewDecoder(body).Decode(r) }
+func (r *ListItemsResponse) SetBody(body io.Reader) error { return nil }
+func (r *ListItemsResponse) AcceptContentType() string    { return "application/json" }
+func (r *ListItemsResponse) SetStatusCode(code int) error { return nil }
+func (r *ListItemsResponse) SetHeaders(headers Headers) error {
+	_ = headers
+	return nil
+}
+func (r *ListItemsResponse) Items() []int       { return r.Data }
+func (r *ListItemsResponse) NextCursor() string { return r.Cursor }
+
+// newItemsServer serves the items 0..total-1 in pages of pageSize items.
+func newItemsServer(total, pageSize int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+
+		start := 0
+		switch {
+		case query.Has("offset"):
+			start, _ = strconv.Atoi(query.Get("offset"))
+		case query.Has("page"):
+			page, _ := strconv.Atoi(query.Get("page"))
+			start = (page - 1) * pageSize
+		case query.Has("cursor"):
+			start, _ = strconv.Atoi(query.Get("cursor"))
+		case query.Has("start"):
+			start, _ = strconv.Atoi(query.Get("start"))
+		}
+
+		response := ListItemsResponse{Data: []int{}}
+		for i := start; i < total && i < start+pageSize; i++ {
+			response.Data = append(response.Data, i)
+		}
+
+		next := start + pageSize
+		if next < total {
+			response.Cursor = strconv.Itoa(next)
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/items?start=%d>; rel="next"`, req.Host, next))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func TestPaginator_Items(t *testing.T) {
+	server := newItemsServer(10, 3)
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		strategy PaginationStrategy
+		maxPages int
+		prefetch int
+		want     int
+		wantErr  error
+	}{
+		{
+			name:     "link header",
+			strategy: NewLinkPagination(),
+			want:     10,
+		},
+		{
+			name:     "cursor",
+			strategy: NewCursorPagination("cursor"),
+			want:     10,
+		},
+		{
+			name:     "offset",
+			strategy: NewOffsetPagination("offset", "limit", 3),
+			want:     10,
+		},
+		{
+			name:     "page number",
+			strategy: NewPageNumberPagination("page", "size", 3),
+			want:     10,
+		},
+		{
+			name:     "prefetch",
+			strategy: NewPageNumberPagination("page", "", 3),
+			prefetch: 2,
+			want:     10,
+		},
+		{
+			name:     "max pages",
+			strategy: NewLinkPagination(),
+			maxPages: 2,
+			want:     6,
+			wantErr:  ErrMaxPages,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paginator := NewPaginator(
+				New(server.URL),
+				&listItemsRequest{},
+				tt.strategy,
+				func() PageResponse[int] { return &ListItemsResponse{} },
+			).WithMaxPages(tt.maxPages).WithPrefetch(tt.prefetch)
+
+			var got []int
+			var gotErr error
+			for item, err := range paginator.Items(context.Background()) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				got = append(got, item)
+			}
+
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("Paginator.Items() error = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+
+			if len(got) != tt.want {
+				t.Fatalf("Paginator.Items() got %d items, want %d", len(got), tt.want)
+			}
+
+			for i, item := range got {
+				if item != i {
+					t.Errorf("Paginator.Items() item %d = %d, want %d", i, item, i)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginator_AbsoluteLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		response := ListItemsResponse{Data: []int{0}}
+		switch {
+		case req.URL.Path == "/v1/items" && req.URL.Query().Get("page") == "2":
+			response.Data = []int{1}
+		case req.URL.Path == "/v/items" || req.URL.Path == "/v1/items":
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/v1/items?page=2>; rel="next"`, req.Host))
+		default:
+			http.NotFound(w, req)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		endpoint string
+	}{
+		{
+			name:     "endpoint prefix of the link path",
+			endpoint: server.URL + "/v",
+		},
+		{
+			name:     "endpoint with query",
+			endpoint: server.URL + "/v1?key=k",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paginator := NewPaginator(
+				New(tt.endpoint),
+				&listItemsRequest{},
+				NewLinkPagination(),
+				func() PageResponse[int] { return &ListItemsResponse{} },
+			)
+
+			var got []int
+			for item, err := range paginator.Items(context.Background()) {
+				if err != nil {
+					t.Fatalf("Paginator.Items() error = %v", err)
+				}
+				got = append(got, item)
+			}
+
+			if len(got) != 2 || got[0] != 0 || got[1] != 1 {
+				t.Errorf("Paginator.Items() = %v, want [0 1]", got)
+			}
+		})
+	}
+}
+
+// inflightTransport counts the requests in flight, slowed down so that prefetched pages
+// are still being fetched when the consumer stops.
+type inflightTransport struct {
+	inflight atomic.Int32
+}
+
+func (t *inflightTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.inflight.Add(1)
+	defer t.inflight.Add(-1)
+
+	time.Sleep(10 * time.Millisecond)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestPaginator_Break(t *testing.T) {
+	server := newItemsServer(100, 1)
+	defer server.Close()
+
+	transport := &inflightTransport{}
+	paginator := NewPaginator(
+		New(server.URL).WithHTTPClient(&http.Client{Transport: transport}),
+		&listItemsRequest{},
+		NewOffsetPagination("offset", "limit", 1),
+		func() PageResponse[int] { return &ListItemsResponse{} },
+	).WithPrefetch(5)
+
+	count := 0
+	for _, err := range paginator.Items(context.Background()) {
+		if err != nil {
+			t.Fatalf("Paginator.Items() error = %v", err)
+		}
+		count++
+		if count == 3 {
+			break
+		}
+	}
+
+	if count != 3 {
+		t.Errorf("Paginator.Items() got %d items, want 3", count)
+	}
+
+	if inflight := transport.inflight.Load(); inflight != 0 {
+		t.Errorf("Paginator.Items() left %d requests in flight", inflight)
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{
+			name:   "single",
+			values: []string{`<https://example.com/items?page=2>; rel="next"`},
+			want:   "https://example.com/items?page=2",
+		},
+		{
+			name:   "multiple",
+			values: []string{`<https://example.com/items?page=1>; rel="prev", <https://example.com/items?page=3>; rel="next"`},
+			want:   "https://example.com/items?page=3",
+		},
+		{
+			name:   "multiple rel values",
+			values: []string{`</items?page=2>; rel="last next"`},
+			want:   "/items?page=2",
+		},
+		{
+			name:   "comma in url",
+			values: []string{`</items?fields=a,b&page=1>; rel="prev"; title="a, b", </items?fields=a,b&page=2>; rel="next"`},
+			want:   "/items?fields=a,b&page=2",
+		},
+		{
+			name:   "missing",
+			values: []string{`<https://example.com/items?page=1>; rel="prev"`},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextLink(tt.values); got != tt.want {
+				t.Errorf("nextLink() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package restclientgo
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// PathTemplate is a request path with named placeholders, as in "/users/{id}/posts".
+// Simple placeholders are path escaped, so values containing "/", "?" or spaces
+// stay within their segment. Reserved placeholders, as in "/files/{+path}", keep
+// reserved characters and percent-encoded triplets following RFC 6570 level 2.
+type PathTemplate string
+
+// PathParams are the values of the placeholders of a PathTemplate. Values are
+// formatted with fmt.Sprint.
+type PathParams map[string]any
+
+// Expand replaces the placeholders of the template with params. It returns an error
+// wrapping ErrPathTemplate if the template is malformed, a placeholder has no value
+// or a param is not used by the template.
+func (t PathTemplate) Expand(params PathParams) (string, error) {
+	var expanded strings.Builder
+	used := make(map[string]bool, len(params))
+
+	template := string(t)
+	for {
+		start := strings.IndexAny(template, "{}")
+		if start < 0 {
+			expanded.WriteString(template)
+			break
+		}
+
+		if template[start] == '}' {
+			return "", fmt.Errorf("%w: unexpected } in %s", ErrPathTemplate, t)
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%w: unclosed { in %s", ErrPathTemplate, t)
+		}
+		end += start
+
+		expanded.WriteString(template[:start])
+
+		name, reserved := strings.CutPrefix(template[start+1:end], "+")
+		if !validPlaceholder(name) {
+			return "", fmt.Errorf("%w: invalid placeholder {%s} in %s", ErrPathTemplate, template[start+1:end], t)
+		}
+
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("%w: missing value for {%s} in %s", ErrPathTemplate, name, t)
+		}
+		used[name] = true
+
+		if reserved {
+			expanded.WriteString(escapeReserved(fmt.Sprint(value)))
+		} else {
+			expanded.WriteString(url.PathEscape(fmt.Sprint(value)))
+		}
+
+		template = template[end+1:]
+	}
+
+	var unused []string
+	for name := range params {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return "", fmt.Errorf("%w: unused params %s in %s", ErrPathTemplate, strings.Join(unused, ", "), t)
+	}
+
+	return expanded.String(), nil
+}
+
+func validPlaceholder(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, c := range name {
+		if !isUnreserved(c) || c == '-' || c == '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// escapeReserved percent-encodes value except for unreserved and reserved characters
+// and existing percent-encoded triplets.
+func escapeReserved(value string) string {
+	const hex = "0123456789ABCDEF"
+
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '%' && i+2 < len(value) && isHex(value[i+1]) && isHex(value[i+2]):
+			escaped.WriteString(value[i : i+3])
+			i += 2
+		case c < 0x80 && (isUnreserved(rune(c)) || strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0):
+			escaped.WriteByte(c)
+		default:
+			escaped.WriteByte('%')
+			escaped.WriteByte(hex[c>>4])
+			escaped.WriteByte(hex[c&0x0f])
+		}
+	}
+
+	return escaped.String()
+}
+
+func isUnreserved(c rune) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// placeholders returns the names of the placeholders of the template.
+func (t PathTemplate) placeholders() ([]string, error) {
+	var names []string
+
+	template := string(t)
+	for {
+		start := strings.IndexAny(template, "{}")
+		if start < 0 {
+			return names, nil
+		}
+
+		end := strings.IndexByte(template[start:], '}')
+		if template[start] == '}' || end < 0 {
+			return nil, fmt.Errorf("%w: unbalanced braces in %s", ErrPathTemplate, t)
+		}
+		end += start
+
+		name := strings.TrimPrefix(template[start+1:end], "+")
+		if !validPlaceholder(name) {
+			return nil, fmt.Errorf("%w: invalid placeholder {%s} in %s", ErrPathTemplate, template[start+1:end], t)
+		}
+		names = append(names, name)
+
+		template = template[end+1:]
+	}
+}
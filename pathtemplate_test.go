@@ -0,0 +1,81 @@
+package restclientgo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPathTemplate_Expand(t *testing.T) {
+	tests := []struct {
+		name     string
+		template PathTemplate
+		params   PathParams
+		want     string
+		wantErr  error
+	}{
+		{
+			name:     "simple",
+			template: "/users/{id}/posts/{postID}",
+			params:   PathParams{"id": "42", "postID": 7},
+			want:     "/users/42/posts/7",
+		},
+		{
+			name:     "escaped",
+			template: "/users/{id}/posts",
+			params:   PathParams{"id": "a/b?c d"},
+			want:     "/users/a%2Fb%3Fc%20d/posts",
+		},
+		{
+			name:     "reserved",
+			template: "/files/{+path}",
+			params:   PathParams{"path": "docs/a b/100%25?x=1"},
+			want:     "/files/docs/a%20b/100%25?x=1",
+		},
+		{
+			name:     "reserved stray percent",
+			template: "/files/{+path}",
+			params:   PathParams{"path": "50%"},
+			want:     "/files/50%25",
+		},
+		{
+			name:     "no placeholders",
+			template: "/todos",
+			want:     "/todos",
+		},
+		{
+			name:     "missing param",
+			template: "/users/{id}",
+			params:   PathParams{},
+			wantErr:  ErrPathTemplate,
+		},
+		{
+			name:     "unused param",
+			template: "/users/{id}",
+			params:   PathParams{"id": 1, "name": "x"},
+			wantErr:  ErrPathTemplate,
+		},
+		{
+			name:     "unclosed placeholder",
+			template: "/users/{id",
+			params:   PathParams{"id": 1},
+			wantErr:  ErrPathTemplate,
+		},
+		{
+			name:     "invalid placeholder",
+			template: "/users/{}",
+			wantErr:  ErrPathTemplate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.template.Expand(tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PathTemplate.Expand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("PathTemplate.Expand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
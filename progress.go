@@ -0,0 +1,149 @@
+package restclientgo
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+const defaultProgressInterval = 100 * time.Millisecond
+
+// ProgressDirection tells whether a Progress refers to the request or to the response body.
+type ProgressDirection int
+
+const (
+	ProgressUpload ProgressDirection = iota
+	ProgressDownload
+)
+
+// Progress reports the bytes transferred of a request or response body.
+type Progress struct {
+	Direction ProgressDirection
+	// Transferred is the number of bytes transferred so far.
+	Transferred int64
+	// Total is the size of the body, or -1 if unknown.
+	Total int64
+	// Elapsed is the time elapsed since the transfer started.
+	Elapsed time.Duration
+	// BytesPerSecond is the average throughput of the transfer.
+	BytesPerSecond float64
+	// Done is true when the transfer is completed.
+	Done bool
+}
+
+type ProgressCallback func(Progress)
+
+type ProgressTracker interface {
+	// ProgressCallback returns the callback receiving the request progress, if any.
+	ProgressCallback() ProgressCallback
+}
+
+// WithProgress sets a callback receiving the upload and download progress of each request.
+func (r *RestClient) WithProgress(progressCallback ProgressCallback) *RestClient {
+	r.progressCallback = progressCallback
+	return r
+}
+
+// WithProgressInterval sets the minimum interval between two progress reports of a transfer.
+func (r *RestClient) WithProgressInterval(interval time.Duration) *RestClient {
+	r.progressInterval = interval
+	return r
+}
+
+// progressCallbackFor returns the progress callback of the request, falling back to the client one.
+func (r *RestClient) progressCallbackFor(request Request) ProgressCallback {
+	if tracker, isTracker := requestAs[ProgressTracker](request); isTracker && tracker.ProgressCallback() != nil {
+		return tracker.ProgressCallback()
+	}
+
+	return r.progressCallback
+}
+
+func (r *RestClient) newProgressReader(
+	body io.ReadCloser,
+	direction ProgressDirection,
+	total int64,
+	callback ProgressCallback,
+) *progressReader {
+	interval := r.progressInterval
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+
+	now := time.Now()
+	return &progressReader{
+		body:     body,
+		callback: callback,
+		interval: interval,
+		start:    now,
+		reported: now,
+		progress: Progress{
+			Direction: direction,
+			Total:     total,
+		},
+	}
+}
+
+// progressReader reports the bytes read from the wrapped body, at most once per interval.
+type progressReader struct {
+	body     io.ReadCloser
+	callback ProgressCallback
+	interval time.Duration
+	start    time.Time
+	reported time.Time
+	progress Progress
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.body.Read(b)
+	p.progress.Transferred += int64(n)
+
+	now := time.Now()
+	if errors.Is(err, io.EOF) {
+		p.report(now, true)
+	} else if now.Sub(p.reported) >= p.interval {
+		p.report(now, false)
+	}
+
+	return n, err
+}
+
+func (p *progressReader) Close() error {
+	p.report(time.Now(), true)
+	return p.body.Close()
+}
+
+func (p *progressReader) report(now time.Time, done bool) {
+	if p.progress.Done {
+		return
+	}
+
+	p.reported = now
+	p.progress.Done = done
+	p.progress.Elapsed = now.Sub(p.start)
+	if seconds := p.progress.Elapsed.Seconds(); seconds > 0 {
+		p.progress.BytesPerSecond = float64(p.progress.Transferred) / seconds
+	}
+
+	p.callback(p.progress)
+}
+
+// trackRequestProgress wraps the request body to report the upload progress.
+func (r *RestClient) trackRequestProgress(httpRequest *http.Request, callback ProgressCallback) {
+	if httpRequest.Body == nil || httpRequest.Body == http.NoBody {
+		return
+	}
+
+	total := httpRequest.ContentLength
+	if total == 0 {
+		total = -1
+	}
+
+	httpRequest.Body = r.newProgressReader(httpRequest.Body, ProgressUpload, total, callback)
+}
+
+// trackResponseProgress wraps the response body to report the download progress.
+func (r *RestClient) trackResponseProgress(httpResponse *http.Response, callback ProgressCallback) {
+	httpResponse.Body = r.newProgressReader(httpResponse.Body, ProgressDownload, httpResponse.ContentLength, callback)
+}
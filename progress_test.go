@@ -0,0 +1,110 @@
+package restclientgo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type uploadRequest struct {
+	body             []byte
+	progressCallback ProgressCallback
+}
+
+func (r *uploadRequest) Path() (string, error)              { return "/upload", nil }
+func (r *uploadRequest) Encode() (io.Reader, error)         { return bytes.NewReader(r.body), nil }
+func (r *uploadRequest) ContentType() string                { return "application/octet-stream" }
+func (r *uploadRequest) ProgressCallback() ProgressCallback { return r.progressCallback }
+
+type progressRecorder struct {
+	mu       sync.Mutex
+	progress []Progress
+}
+
+func (p *progressRecorder) record(progress Progress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress = append(p.progress, progress)
+}
+
+func (p *progressRecorder) last(direction ProgressDirection) (Progress, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var last Progress
+	count := 0
+	for _, progress := range p.progress {
+		if progress.Direction == direction {
+			last = progress
+			count++
+		}
+	}
+
+	return last, count
+}
+
+func TestRestClient_Progress(t *testing.T) {
+	body := bytes.Repeat([]byte("x"), 1<<20)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Length", strconv.FormatInt(req.ContentLength, 10))
+		_, _ = io.Copy(w, req.Body)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		clientLevel   bool
+		interval      time.Duration
+		wantMaxEvents int
+	}{
+		{
+			name:          "client level",
+			clientLevel:   true,
+			interval:      time.Hour,
+			wantMaxEvents: 1,
+		},
+		{
+			name:          "request level",
+			interval:      time.Nanosecond,
+			wantMaxEvents: len(body),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &progressRecorder{}
+			restClient := New(server.URL).WithProgressInterval(tt.interval)
+			request := &uploadRequest{body: body}
+			if tt.clientLevel {
+				restClient.WithProgress(recorder.record)
+			} else {
+				request.progressCallback = recorder.record
+			}
+
+			response := &UploadResponse{}
+			if err := restClient.Post(context.Background(), request, response); err != nil {
+				t.Fatalf("RestClient.Post() error = %v", err)
+			}
+
+			for _, direction := range []ProgressDirection{ProgressUpload, ProgressDownload} {
+				last, count := recorder.last(direction)
+				if !last.Done {
+					t.Errorf("direction %d: last progress not done", direction)
+				}
+				if last.Transferred != int64(len(body)) || last.Total != int64(len(body)) {
+					t.Errorf("direction %d: progress = %d/%d, want %d/%d",
+						direction, last.Transferred, last.Total, len(body), len(body))
+				}
+				if count < 1 || count > tt.wantMaxEvents {
+					t.Errorf("direction %d: got %d progress events, want at most %d", direction, count, tt.wantMaxEvents)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package restclientgo
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// queryLayouts are the time layouts that can be referenced by name in the layout tag.
+var queryLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"DateOnly":    time.DateOnly,
+	"DateTime":    time.DateTime,
+	"TimeOnly":    time.TimeOnly,
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// EncodeQuery encodes the exported fields of the struct v, or of the struct pointed by v,
+// into URLValues. Fields are named by their url tag, as in `url:"name,omitempty"`, or by
+// their Go name. A "-" name skips the field, and omitempty skips zero values. Nil pointers
+// are always skipped and embedded structs are encoded as if their fields were part of the
+// outer struct.
+//
+// Slices and arrays are encoded as arrays, maps with string keys and nested structs as
+// objects, using the QueryStyle named by the style option and the explode option, as in
+// `url:"filter,style=deepObject"` or `url:"ids,explode=false"`. The form style is the
+// default. With the deepObject, brackets and dot styles, the properties of nested structs
+// inherit the style and keep the full key path, as in filter[range][from]=1.
+//
+// Strings, bools, all int, uint and float widths, time.Duration, fmt.Stringer and
+// encoding.TextMarshaler values are supported. time.Time values are formatted with
+// RFC 3339 unless a layout tag sets a Go layout, the name of a time package layout such
+// as "DateOnly", or "unix" and "unixmilli" for epoch timestamps.
+func EncodeQuery(v any) (*URLValues, error) {
+	values := NewURLValues()
+	if err := encodeQuery(values.AddValue, v); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// EncodeOrderedQuery encodes v like EncodeQuery into OrderedURLValues, keeping the order
+// of the struct fields.
+func EncodeOrderedQuery(v any) (*OrderedURLValues, error) {
+	values := NewOrderedURLValues()
+	if err := encodeQuery(values.AddValue, v); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func encodeQuery(add queryAdder, v any) error {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T is not a struct", ErrQueryEncode, v)
+	}
+
+	return encodeQueryStruct(add, value, nil)
+}
+
+// queryField is a struct field encoded as a query parameter.
+type queryField struct {
+	name      string
+	omitEmpty bool
+	layout    string
+	style     QueryStyle
+	explode   bool
+}
+
+func parseQueryField(field reflect.StructField) (queryField, bool, error) {
+	tag, hasTag := field.Tag.Lookup("url")
+	if tag == "-" {
+		return queryField{}, false, nil
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if !hasTag || name == "" {
+		name = field.Name
+	}
+
+	queryField, err := parseQueryOptions(name, options, field.Tag.Get("layout"))
+	return queryField, err == nil, err
+}
+
+// parseQueryOptions parses the omitempty, style and explode options of a query field.
+func parseQueryOptions(name, options, layout string) (queryField, error) {
+	queryField := queryField{name: name, layout: layout, explode: true}
+	explodeSet := false
+	for _, option := range strings.Split(options, ",") {
+		option, value, _ := strings.Cut(option, "=")
+		switch option {
+		case "omitempty":
+			queryField.omitEmpty = true
+		case "style":
+			style, err := ParseQueryStyle(value)
+			if err != nil {
+				return queryField, err
+			}
+			queryField.style = style
+		case "explode":
+			explodeSet = true
+			queryField.explode = value != "false"
+		}
+	}
+
+	if !explodeSet {
+		queryField.explode = queryField.style.DefaultExplode()
+	}
+
+	return queryField, nil
+}
+
+// nested returns the property field of the object field f, named with the full key
+// path and encoded with the style of f.
+func (f queryField) nested(property queryField) queryField {
+	property.name = f.style.nestedKey(f.name, property.name)
+	property.style = f.style
+	property.explode = f.explode
+
+	return property
+}
+
+// encodeQueryStruct encodes the fields of value, as properties of the object field
+// parent if not nil.
+func encodeQueryStruct(add queryAdder, value reflect.Value, parent *queryField) error {
+	valueType := value.Type()
+	for i := range valueType.NumField() {
+		field := valueType.Field(i)
+		fieldValue := value.Field(i)
+
+		if field.Anonymous && !hasQueryName(field) && isEmbeddedStruct(field.Type) {
+			if field.Type.Kind() == reflect.Pointer {
+				if fieldValue.IsNil() {
+					continue
+				}
+				fieldValue = fieldValue.Elem()
+			}
+			if err := encodeQueryStruct(add, fieldValue, parent); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		queryField, ok, err := parseQueryField(field)
+		if err == nil && ok {
+			if parent != nil {
+				queryField = parent.nested(queryField)
+			}
+			err = encodeQueryField(add, queryField, fieldValue)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: field %s: %w", ErrQueryEncode, field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func hasQueryName(field reflect.StructField) bool {
+	name, _, _ := strings.Cut(field.Tag.Get("url"), ",")
+	return name != ""
+}
+
+func isEmbeddedStruct(fieldType reflect.Type) bool {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	return fieldType.Kind() == reflect.Struct && !isQueryScalar(fieldType)
+}
+
+func encodeQueryField(add queryAdder, field queryField, value reflect.Value) error {
+	if field.omitEmpty && value.IsZero() {
+		return nil
+	}
+
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	if isQueryScalar(value.Type()) {
+		formatted, ok, err := formatQueryValue(value, field.layout)
+		if err != nil || !ok {
+			return err
+		}
+
+		add(field.name, formatted)
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		if field.omitEmpty && value.Len() == 0 {
+			return nil
+		}
+
+		elements := make([]string, 0, value.Len())
+		for i := range value.Len() {
+			element, ok, err := formatQueryValue(value.Index(i), field.layout)
+			if err != nil {
+				return err
+			}
+			if ok {
+				elements = append(elements, element)
+			}
+		}
+
+		addQueryArray(add, field.name, elements, field.style, field.explode)
+	case reflect.Map:
+		pairs, err := queryMapPairs(value, field.layout)
+		if err != nil {
+			return err
+		}
+
+		if field.omitEmpty && len(pairs) == 0 {
+			return nil
+		}
+
+		addQueryObject(add, field.name, pairs, field.style, field.explode)
+	case reflect.Struct:
+		if field.style.nestsObjects() {
+			return encodeQueryStruct(add, value, &field)
+		}
+
+		var pairs []queryPair
+		err := encodeQueryStruct(func(key, value string) {
+			pairs = append(pairs, queryPair{key: key, value: value})
+		}, value, nil)
+		if err != nil {
+			return err
+		}
+
+		addQueryObject(add, field.name, pairs, field.style, field.explode)
+	default:
+		formatted, ok, err := formatQueryValue(value, field.layout)
+		if err != nil || !ok {
+			return err
+		}
+
+		add(field.name, formatted)
+	}
+
+	return nil
+}
+
+// queryMapPairs formats the entries of a map with string keys, sorted by key.
+func queryMapPairs(value reflect.Value, layout string) ([]queryPair, error) {
+	if value.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("unsupported map key type %s", value.Type().Key())
+	}
+
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	pairs := make([]queryPair, 0, len(keys))
+	for _, key := range keys {
+		formatted, ok, err := formatQueryValue(value.MapIndex(key), layout)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			pairs = append(pairs, queryPair{key: key.String(), value: formatted})
+		}
+	}
+
+	return pairs, nil
+}
+
+// isQueryScalar reports whether values of type t are formatted as a single query value.
+func isQueryScalar(t reflect.Type) bool {
+	if t == timeType || t.Implements(textMarshalerType) || t.Implements(stringerType) {
+		return true
+	}
+
+	pointerType := reflect.PointerTo(t)
+	return pointerType.Implements(textMarshalerType) || pointerType.Implements(stringerType)
+}
+
+// formatQueryValue formats a scalar value. It returns false for nil pointers.
+func formatQueryValue(value reflect.Value, layout string) (string, bool, error) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", false, nil
+		}
+		value = value.Elem()
+	}
+
+	if t, isTime := value.Interface().(time.Time); isTime {
+		return formatQueryTime(t, layout), true, nil
+	}
+
+	if !value.CanAddr() {
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		value = addressable
+	}
+
+	switch v := value.Addr().Interface().(type) {
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return "", false, err
+		}
+		return string(text), true, nil
+	case fmt.Stringer:
+		return v.String(), true, nil
+	}
+
+	return formatQueryKind(value)
+}
+
+// formatQueryKind formats a value by its kind.
+func formatQueryKind(value reflect.Value) (string, bool, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String(), true, nil
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits()), true, nil
+	default:
+		return "", false, fmt.Errorf("unsupported type %s", value.Type())
+	}
+}
+
+func formatQueryTime(t time.Time, layout string) string {
+	switch layout {
+	case "":
+		return t.Format(time.RFC3339)
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "unixmilli":
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+
+	if namedLayout, ok := queryLayouts[layout]; ok {
+		layout = namedLayout
+	}
+
+	return t.Format(layout)
+}
@@ -0,0 +1,106 @@
+package restclientgo
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+type queryPagination struct {
+	Page  int `url:"page,omitempty"`
+	Limit int `url:"limit"`
+}
+
+type queryStatus int
+
+func (s queryStatus) String() string {
+	return [...]string{"open", "closed"}[s]
+}
+
+type searchQuery struct {
+	queryPagination
+	Query    string        `url:"q"`
+	Tags     []string      `url:"tag,omitempty"`
+	Since    time.Time     `url:"since,omitempty" layout:"DateOnly"`
+	Until    *time.Time    `url:"until" layout:"unix"`
+	Timeout  time.Duration `url:"timeout,omitempty"`
+	Status   queryStatus   `url:"status"`
+	IP       net.IP        `url:"ip,omitempty"`
+	Ratio    float32       `url:"ratio"`
+	Size     uint8         `url:"size"`
+	Archived *bool         `url:"archived"`
+	Internal string        `url:"-"`
+	Default  string
+	private  string
+}
+
+func TestEncodeQuery(t *testing.T) {
+	since := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	archived := false
+
+	tests := []struct {
+		name    string
+		v       any
+		want    string
+		wantErr error
+	}{
+		{
+			name: "all types",
+			v: &searchQuery{
+				queryPagination: queryPagination{Page: 2, Limit: 10},
+				Query:           "a b&c",
+				Tags:            []string{"x", "y"},
+				Since:           since,
+				Until:           &since,
+				Timeout:         90 * time.Second,
+				Status:          1,
+				IP:              net.IPv4(10, 0, 0, 1),
+				Ratio:           0.1,
+				Size:            255,
+				Archived:        &archived,
+				Internal:        "hidden",
+				Default:         "d",
+				private:         "p",
+			},
+			want: "Default=d&archived=false&ip=10.0.0.1&limit=10&page=2&q=a+b%26c&ratio=0.1&since=2024-03-01" +
+				"&size=255&status=closed&tag=x&tag=y&timeout=1m30s&until=1709287200",
+		},
+		{
+			name: "zero values",
+			v:    searchQuery{},
+			want: "Default=&limit=0&q=&ratio=0&size=0&status=open",
+		},
+		{
+			name: "nil pointer",
+			v:    (*searchQuery)(nil),
+			want: "",
+		},
+		{
+			name:    "not a struct",
+			v:       "q=1",
+			wantErr: ErrQueryEncode,
+		},
+		{
+			name:    "unsupported type",
+			v:       struct{ Done chan bool }{Done: make(chan bool)},
+			wantErr: ErrQueryEncode,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodeQuery(tt.v)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("EncodeQuery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got.Encode() != tt.want {
+				t.Errorf("EncodeQuery() = %v, want %v", got.Encode(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package restclientgo
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// QueryFieldError is the error decoding a query parameter into a struct field.
+type QueryFieldError struct {
+	Field string
+	Key   string
+	Err   error
+}
+
+func (e *QueryFieldError) Error() string {
+	return fmt.Sprintf("%s: field %s (%s): %s", ErrQueryDecode, e.Field, e.Key, e.Err)
+}
+
+func (e *QueryFieldError) Unwrap() []error {
+	return []error{ErrQueryDecode, e.Err}
+}
+
+// DecodeQuery decodes values into the struct pointed by v, honoring the url tags, the
+// layout tags and the array and object styles used by EncodeQuery. Fields without a
+// value are left untouched, as are empty values of non-string fields. Every field that
+// cannot be decoded is reported as a QueryFieldError, joined in the returned error.
+//
+// Types formatted with fmt.Stringer must implement encoding.TextUnmarshaler to be decoded,
+// with the exception of time.Duration.
+func DecodeQuery(values url.Values, v any) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T is not a pointer to a struct", ErrQueryDecode, v)
+	}
+
+	return errors.Join(decodeQueryStruct(values, value.Elem(), nil, "", "")...)
+}
+
+// decodeQueryStruct decodes values into the fields of value, as properties of the object
+// field parent if not nil. Nested structs report their errors with the field and key paths
+// of the outer fields.
+func decodeQueryStruct(values url.Values, value reflect.Value, parent *queryField, fieldPath, keyPath string) []error {
+	var errs []error
+
+	valueType := value.Type()
+	for i := range valueType.NumField() {
+		field := valueType.Field(i)
+		fieldValue := value.Field(i)
+
+		if field.Anonymous && !hasQueryName(field) && isEmbeddedStruct(field.Type) {
+			if field.Type.Kind() == reflect.Pointer {
+				if !fieldValue.CanSet() {
+					continue
+				}
+				if fieldValue.IsNil() {
+					fieldValue.Set(reflect.New(field.Type.Elem()))
+				}
+				fieldValue = fieldValue.Elem()
+			}
+			errs = append(errs, decodeQueryStruct(values, fieldValue, parent, fieldPath, keyPath)...)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		queryField, ok, err := parseQueryField(field)
+		if err == nil && ok && parent != nil {
+			queryField = parent.nested(queryField)
+		}
+		if err == nil && ok {
+			if isNestedQueryStruct(field.Type) {
+				var object url.Values
+				var found bool
+				object, found, err = queryObjectValues(values, queryField)
+				switch {
+				case err != nil || !found:
+				case queryField.style.nestsObjects():
+					// the keys of the properties hold the full key path
+					errs = append(errs, decodeQueryStruct(values, settableValue(fieldValue), &queryField,
+						fieldPath+field.Name+".", keyPath)...)
+				default:
+					errs = append(errs, decodeQueryStruct(object, settableValue(fieldValue), nil,
+						fieldPath+field.Name+".", keyPath+queryField.name+".")...)
+				}
+			} else {
+				err = decodeQueryField(values, queryField, fieldValue)
+			}
+		}
+		if err != nil {
+			errs = append(errs, &QueryFieldError{Field: fieldPath + field.Name, Key: keyPath + queryField.name, Err: err})
+		}
+	}
+
+	return errs
+}
+
+func decodeQueryField(values url.Values, field queryField, value reflect.Value) error {
+	valueType := value.Type()
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+
+	if isQueryScalar(valueType) || isQueryUnmarshaler(valueType) {
+		return decodeQueryScalar(values, field, value)
+	}
+
+	switch valueType.Kind() {
+	case reflect.Slice, reflect.Array:
+		elements, found := queryArrayValues(values, field)
+		if !found {
+			return nil
+		}
+		return decodeQueryArray(elements, field, settableValue(value))
+	case reflect.Map:
+		object, found, err := queryObjectValues(values, field)
+		if err != nil || !found {
+			return err
+		}
+		return decodeQueryMap(object, field, settableValue(value))
+	default:
+		return decodeQueryScalar(values, field, value)
+	}
+}
+
+func decodeQueryScalar(values url.Values, field queryField, value reflect.Value) error {
+	queryValues, found := values[field.name]
+	if !found || len(queryValues) == 0 {
+		return nil
+	}
+
+	return parseQueryValue(queryValues[0], field.layout, value)
+}
+
+func decodeQueryArray(elements []string, field queryField, value reflect.Value) error {
+	if value.Kind() == reflect.Array {
+		if len(elements) > value.Len() {
+			return fmt.Errorf("%d values exceed array length %d", len(elements), value.Len())
+		}
+	} else {
+		value.Set(reflect.MakeSlice(value.Type(), len(elements), len(elements)))
+	}
+
+	for i, element := range elements {
+		if err := parseQueryValue(element, field.layout, value.Index(i)); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func decodeQueryMap(object url.Values, field queryField, value reflect.Value) error {
+	mapType := value.Type()
+	if mapType.Key().Kind() != reflect.String {
+		return fmt.Errorf("unsupported map key type %s", mapType.Key())
+	}
+
+	if value.IsNil() {
+		value.Set(reflect.MakeMapWithSize(mapType, len(object)))
+	}
+
+	for key, queryValues := range object {
+		element := reflect.New(mapType.Elem()).Elem()
+		if err := parseQueryValue(queryValues[0], field.layout, element); err != nil {
+			return fmt.Errorf("key %s: %w", key, err)
+		}
+		value.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), element)
+	}
+
+	return nil
+}
+
+// queryArrayValues returns the elements of the array encoded with the style of field.
+func queryArrayValues(values url.Values, field queryField) ([]string, bool) {
+	switch field.style {
+	case QueryStyleDeepObject, QueryStyleBrackets:
+		elements, found := values[field.name+"[]"]
+		return elements, found
+	}
+
+	elements, found := values[field.name]
+	if !found || field.explode || field.style == QueryStyleDot || len(elements) == 0 {
+		return elements, found
+	}
+
+	if elements[0] == "" {
+		return nil, true
+	}
+
+	return strings.Split(elements[0], field.style.delimiter()), true
+}
+
+// queryObjectValues returns the properties of the object encoded with the style of field.
+func queryObjectValues(values url.Values, field queryField) (url.Values, bool, error) {
+	var prefix, suffix string
+	switch {
+	case field.style == QueryStyleDeepObject || field.style == QueryStyleBrackets:
+		prefix, suffix = field.name+"[", "]"
+	case field.style == QueryStyleDot:
+		prefix = field.name + "."
+	case field.explode:
+		return values, true, nil
+	default:
+		queryValues, found := values[field.name]
+		if !found || len(queryValues) == 0 || queryValues[0] == "" {
+			return nil, false, nil
+		}
+
+		flattened := strings.Split(queryValues[0], field.style.delimiter())
+		if len(flattened)%2 != 0 {
+			return nil, false, fmt.Errorf("odd number of object keys and values in %q", queryValues[0])
+		}
+
+		object := url.Values{}
+		for i := 0; i < len(flattened); i += 2 {
+			object.Add(flattened[i], flattened[i+1])
+		}
+		return object, true, nil
+	}
+
+	object := url.Values{}
+	for key, queryValues := range values {
+		name, found := strings.CutPrefix(key, prefix)
+		if !found {
+			continue
+		}
+		name, found = strings.CutSuffix(name, suffix)
+		if found && name != "" {
+			object[name] = queryValues
+		}
+	}
+
+	return object, len(object) > 0, nil
+}
+
+// settableValue allocates nil pointers and returns the value they point to.
+func settableValue(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+
+	return value
+}
+
+func isNestedQueryStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Struct && !isQueryScalar(t) && !isQueryUnmarshaler(t)
+}
+
+func isQueryUnmarshaler(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// parseQueryValue parses a scalar query value into value, allocating nil pointers.
+func parseQueryValue(queryValue string, layout string, value reflect.Value) error {
+	valueType := value.Type()
+	for valueType.Kind() == reflect.Pointer {
+		valueType = valueType.Elem()
+	}
+
+	if queryValue == "" && valueType.Kind() != reflect.String {
+		return nil
+	}
+
+	value = settableValue(value)
+
+	switch {
+	case valueType == timeType:
+		t, err := parseQueryTime(queryValue, layout)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(t))
+		return nil
+	case valueType == durationType:
+		duration, err := time.ParseDuration(queryValue)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(duration))
+		return nil
+	case isQueryUnmarshaler(valueType):
+		if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return unmarshaler.UnmarshalText([]byte(queryValue))
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(queryValue)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(queryValue)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(queryValue, 10, valueType.Bits())
+		if err != nil {
+			return err
+		}
+		value.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(queryValue, 10, valueType.Bits())
+		if err != nil {
+			return err
+		}
+		value.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(queryValue, valueType.Bits())
+		if err != nil {
+			return err
+		}
+		value.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", valueType)
+	}
+
+	return nil
+}
+
+func parseQueryTime(queryValue string, layout string) (time.Time, error) {
+	switch layout {
+	case "":
+		return time.Parse(time.RFC3339, queryValue)
+	case "unix", "unixmilli":
+		epoch, err := strconv.ParseInt(queryValue, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if layout == "unix" {
+			return time.Unix(epoch, 0).UTC(), nil
+		}
+		return time.UnixMilli(epoch).UTC(), nil
+	}
+
+	if namedLayout, ok := queryLayouts[layout]; ok {
+		layout = namedLayout
+	}
+
+	return time.Parse(layout, queryValue)
+}
@@ -0,0 +1,147 @@
+package restclientgo
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type decodedQuery struct {
+	queryPagination
+	Query    string        `url:"q"`
+	Tags     []string      `url:"tag,omitempty"`
+	Since    time.Time     `url:"since,omitempty" layout:"DateOnly"`
+	Until    *time.Time    `url:"until" layout:"unix"`
+	Timeout  time.Duration `url:"timeout,omitempty"`
+	IP       net.IP        `url:"ip,omitempty"`
+	Ratio    float32       `url:"ratio"`
+	Size     uint8         `url:"size"`
+	Archived *bool         `url:"archived"`
+	Internal string        `url:"-"`
+}
+
+func TestDecodeQuery_RoundTrip(t *testing.T) {
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC)
+	archived := true
+
+	styled := styledQuery{
+		IDs:    []int{1, 2},
+		Tags:   []string{"a", "b"},
+		Sort:   []string{"-created"},
+		Filter: map[string]string{"status": "open", "owner": "me"},
+	}
+	styled.Range.From = 10
+	styled.Range.To = 20
+
+	tests := []struct {
+		name string
+		v    any
+		got  any
+	}{
+		{
+			name: "scalars",
+			v: &decodedQuery{
+				queryPagination: queryPagination{Page: 2, Limit: 10},
+				Query:           "a b&c",
+				Tags:            []string{"x", "y"},
+				Since:           since,
+				Until:           &until,
+				Timeout:         90 * time.Second,
+				IP:              net.IPv4(10, 0, 0, 1),
+				Ratio:           0.1,
+				Size:            255,
+				Archived:        &archived,
+			},
+			got: &decodedQuery{},
+		},
+		{
+			name: "styles",
+			v:    &styled,
+			got:  &styledQuery{},
+		},
+		{
+			name: "nested objects",
+			v:    newNestedQuery(),
+			got:  &nestedQuery{},
+		},
+		{
+			name: "absent nested object",
+			v:    &nestedQuery{Dot: nestedQueryObject{N: 2}},
+			got:  &nestedQuery{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := EncodeQuery(tt.v)
+			if err != nil {
+				t.Fatalf("EncodeQuery() error = %v", err)
+			}
+
+			values, err := url.ParseQuery(encoded.Encode())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := DecodeQuery(values, tt.got); err != nil {
+				t.Fatalf("DecodeQuery() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.got, tt.v) {
+				t.Errorf("DecodeQuery() = %+v, want %+v", tt.got, tt.v)
+			}
+		})
+	}
+}
+
+func TestDecodeQuery_Errors(t *testing.T) {
+	values := url.Values{
+		"page":       {"two"},
+		"ratio":      {"0.5"},
+		"size":       {"256"},
+		"range.from": {"ten"},
+	}
+
+	var query struct {
+		queryPagination
+		Ratio float64 `url:"ratio"`
+		Size  uint8   `url:"size"`
+		Range struct {
+			From int `url:"from"`
+		} `url:"range,style=dot"`
+	}
+
+	err := DecodeQuery(values, &query)
+	if !errors.Is(err, ErrQueryDecode) {
+		t.Fatalf("DecodeQuery() error = %v, want %v", err, ErrQueryDecode)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("DecodeQuery() error = %v, want joined field errors", err)
+	}
+
+	var fields []string
+	for _, err := range joined.Unwrap() {
+		var fieldError *QueryFieldError
+		if errors.As(err, &fieldError) {
+			fields = append(fields, fieldError.Field)
+		}
+	}
+
+	want := []string{"Page", "Size", "Range.From"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("DecodeQuery() errors = %v, want %v", fields, want)
+	}
+
+	if query.Ratio != 0.5 {
+		t.Errorf("DecodeQuery() ratio = %v, want %v", query.Ratio, 0.5)
+	}
+
+	if err := DecodeQuery(values, query); !errors.Is(err, ErrQueryDecode) {
+		t.Errorf("DecodeQuery() error = %v, want %v", err, ErrQueryDecode)
+	}
+}
@@ -0,0 +1,151 @@
+package restclientgo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// QueryStyle is the serialization of arrays and objects in query strings. The form,
+// spaceDelimited, pipeDelimited and deepObject styles follow the OpenAPI specification.
+type QueryStyle int
+
+const (
+	// QueryStyleForm encodes arrays as ids=1&ids=2, or ids=1,2 when not exploded, and
+	// objects as name=x&role=y, or filter=name,x,role,y when not exploded.
+	QueryStyleForm QueryStyle = iota
+	// QueryStyleSpaceDelimited encodes non exploded arrays and objects as space
+	// separated values, as in ids=1%202.
+	QueryStyleSpaceDelimited
+	// QueryStylePipeDelimited encodes non exploded arrays and objects as pipe
+	// separated values, as in ids=1|2.
+	QueryStylePipeDelimited
+	// QueryStyleDeepObject encodes objects as filter[name]=x and arrays as ids[]=1.
+	QueryStyleDeepObject
+	// QueryStyleBrackets encodes arrays as ids[]=1&ids[]=2 and objects as filter[name]=x.
+	QueryStyleBrackets
+	// QueryStyleDot encodes objects as filter.name=x and arrays as repeated keys.
+	QueryStyleDot
+)
+
+var queryStyleNames = map[string]QueryStyle{
+	"form":           QueryStyleForm,
+	"spaceDelimited": QueryStyleSpaceDelimited,
+	"pipeDelimited":  QueryStylePipeDelimited,
+	"deepObject":     QueryStyleDeepObject,
+	"brackets":       QueryStyleBrackets,
+	"dot":            QueryStyleDot,
+}
+
+// ParseQueryStyle returns the QueryStyle with the given name, as used in the style
+// option of url tags.
+func ParseQueryStyle(name string) (QueryStyle, error) {
+	style, ok := queryStyleNames[name]
+	if !ok {
+		return QueryStyleForm, fmt.Errorf("%w: unknown query style %q", ErrQueryEncode, name)
+	}
+
+	return style, nil
+}
+
+// DefaultExplode returns the explode default of the style: true for form, false for
+// the other styles, as in OpenAPI.
+func (s QueryStyle) DefaultExplode() bool {
+	return s == QueryStyleForm
+}
+
+// queryAdder receives the key and value pairs of an encoded query.
+type queryAdder func(key, value string)
+
+// queryPair is a key and value of an object encoded in a query string.
+type queryPair struct {
+	key   string
+	value string
+}
+
+// AddArray adds the values of an array with the given style.
+func (p *URLValues) AddArray(key string, values []string, style QueryStyle, explode bool) {
+	addQueryArray(p.AddValue, key, values, style, explode)
+}
+
+// AddObject adds the properties of an object, sorted by name, with the given style.
+func (p *URLValues) AddObject(key string, object map[string]string, style QueryStyle, explode bool) {
+	addQueryObject(p.AddValue, key, sortedQueryPairs(object), style, explode)
+}
+
+func sortedQueryPairs(object map[string]string) []queryPair {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]queryPair, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, queryPair{key: name, value: object[name]})
+	}
+
+	return pairs
+}
+
+func addQueryArray(add queryAdder, key string, values []string, style QueryStyle, explode bool) {
+	switch style {
+	case QueryStyleDeepObject, QueryStyleBrackets:
+		for _, value := range values {
+			add(key+"[]", value)
+		}
+	default:
+		if explode || style == QueryStyleDot {
+			for _, value := range values {
+				add(key, value)
+			}
+			return
+		}
+		add(key, strings.Join(values, style.delimiter()))
+	}
+}
+
+func addQueryObject(add queryAdder, key string, pairs []queryPair, style QueryStyle, explode bool) {
+	switch {
+	case style.nestsObjects():
+		for _, pair := range pairs {
+			add(style.nestedKey(key, pair.key), pair.value)
+		}
+	case explode:
+		for _, pair := range pairs {
+			add(pair.key, pair.value)
+		}
+	default:
+		flattened := make([]string, 0, 2*len(pairs))
+		for _, pair := range pairs {
+			flattened = append(flattened, pair.key, pair.value)
+		}
+		add(key, strings.Join(flattened, style.delimiter()))
+	}
+}
+
+// nestsObjects reports whether the style names the properties of objects after the key
+// of the object, so that nested objects keep the full key path.
+func (s QueryStyle) nestsObjects() bool {
+	return s == QueryStyleDeepObject || s == QueryStyleBrackets || s == QueryStyleDot
+}
+
+// nestedKey returns the key of the property name of the object key.
+func (s QueryStyle) nestedKey(key, name string) string {
+	if s == QueryStyleDot {
+		return key + "." + name
+	}
+
+	return key + "[" + name + "]"
+}
+
+func (s QueryStyle) delimiter() string {
+	switch s {
+	case QueryStyleSpaceDelimited:
+		return " "
+	case QueryStylePipeDelimited:
+		return "|"
+	default:
+		return ","
+	}
+}
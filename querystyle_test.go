@@ -0,0 +1,143 @@
+package restclientgo
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestURLValues_AddArray(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   QueryStyle
+		explode bool
+		want    string
+	}{
+		{name: "form explode", style: QueryStyleForm, explode: true, want: "ids=1&ids=2"},
+		{name: "form", style: QueryStyleForm, want: "ids=1,2"},
+		{name: "space delimited", style: QueryStyleSpaceDelimited, want: "ids=1 2"},
+		{name: "pipe delimited", style: QueryStylePipeDelimited, want: "ids=1|2"},
+		{name: "pipe delimited explode", style: QueryStylePipeDelimited, explode: true, want: "ids=1&ids=2"},
+		{name: "brackets", style: QueryStyleBrackets, want: "ids[]=1&ids[]=2"},
+		{name: "dot", style: QueryStyleDot, want: "ids=1&ids=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := NewURLValues()
+			values.AddArray("ids", []string{"1", "2"}, tt.style, tt.explode)
+
+			if got := unescapeQuery(t, values.Encode()); got != tt.want {
+				t.Errorf("URLValues.AddArray() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLValues_AddObject(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   QueryStyle
+		explode bool
+		want    string
+	}{
+		{name: "form explode", style: QueryStyleForm, explode: true, want: "name=x&role=admin"},
+		{name: "form", style: QueryStyleForm, want: "filter=name,x,role,admin"},
+		{name: "pipe delimited", style: QueryStylePipeDelimited, want: "filter=name|x|role|admin"},
+		{name: "deep object", style: QueryStyleDeepObject, explode: true, want: "filter[name]=x&filter[role]=admin"},
+		{name: "dot", style: QueryStyleDot, want: "filter.name=x&filter.role=admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := NewURLValues()
+			values.AddObject("filter", map[string]string{"role": "admin", "name": "x"}, tt.style, tt.explode)
+
+			if got := unescapeQuery(t, values.Encode()); got != tt.want {
+				t.Errorf("URLValues.AddObject() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type styledQuery struct {
+	IDs    []int             `url:"ids,style=pipeDelimited"`
+	Tags   []string          `url:"tag,explode=false"`
+	Sort   []string          `url:"sort,style=brackets,omitempty"`
+	Filter map[string]string `url:"filter,style=deepObject"`
+	Range  struct {
+		From int `url:"from"`
+		To   int `url:"to,omitempty"`
+	} `url:"range,style=dot"`
+}
+
+func TestEncodeQuery_Styles(t *testing.T) {
+	query := styledQuery{
+		IDs:    []int{1, 2},
+		Tags:   []string{"a", "b"},
+		Filter: map[string]string{"status": "open"},
+	}
+	query.Range.From = 10
+
+	got, err := EncodeQuery(query)
+	if err != nil {
+		t.Fatalf("EncodeQuery() error = %v", err)
+	}
+
+	want := "filter[status]=open&ids=1|2&range.from=10&tag=a,b"
+	if got := unescapeQuery(t, got.Encode()); got != want {
+		t.Errorf("EncodeQuery() = %v, want %v", got, want)
+	}
+
+	_, err = EncodeQuery(struct {
+		IDs []int `url:"ids,style=matrix"`
+	}{})
+	if !errors.Is(err, ErrQueryEncode) {
+		t.Errorf("EncodeQuery() error = %v, want %v", err, ErrQueryEncode)
+	}
+}
+
+type nestedQueryLeaf struct {
+	B    string   `url:"b"`
+	Tags []string `url:"tags,omitempty"`
+}
+
+type nestedQueryObject struct {
+	A nestedQueryLeaf `url:"a"`
+	N int             `url:"n,omitempty"`
+}
+
+type nestedQuery struct {
+	Deep     nestedQueryObject  `url:"deep,style=deepObject"`
+	Brackets *nestedQueryObject `url:"brackets,style=brackets"`
+	Dot      nestedQueryObject  `url:"dot,style=dot"`
+}
+
+func newNestedQuery() *nestedQuery {
+	return &nestedQuery{
+		Deep:     nestedQueryObject{A: nestedQueryLeaf{B: "x", Tags: []string{"t1", "t2"}}},
+		Brackets: &nestedQueryObject{A: nestedQueryLeaf{B: "y"}, N: 1},
+		Dot:      nestedQueryObject{A: nestedQueryLeaf{B: "z"}},
+	}
+}
+
+func TestEncodeQuery_NestedObjects(t *testing.T) {
+	got, err := EncodeQuery(newNestedQuery())
+	if err != nil {
+		t.Fatalf("EncodeQuery() error = %v", err)
+	}
+
+	want := "brackets[a][b]=y&brackets[n]=1&deep[a][b]=x&deep[a][tags][]=t1&deep[a][tags][]=t2&dot.a.b=z"
+	if got := unescapeQuery(t, got.Encode()); got != want {
+		t.Errorf("EncodeQuery() = %v, want %v", got, want)
+	}
+}
+
+func unescapeQuery(t *testing.T, query string) string {
+	t.Helper()
+
+	unescaped, err := url.QueryUnescape(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return unescaped
+}
@@ -0,0 +1,126 @@
+package restclientgo
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// QueryValues is a query string receiving formatted values, implemented by URLValues
+// and OrderedURLValues.
+type QueryValues interface {
+	AddValue(key string, value string)
+}
+
+// Scalar are the types formatted as a single query value by Add.
+type Scalar interface {
+	~string | ~bool |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// ZeroPolicy selects whether nil and zero values are added to the query.
+type ZeroPolicy int
+
+const (
+	// ZeroOmitNil omits nil values and adds zero values.
+	ZeroOmitNil ZeroPolicy = iota
+	// ZeroOmit omits nil and zero values.
+	ZeroOmit
+	// ZeroInclude adds nil values as empty values and zero values as they are.
+	ZeroInclude
+)
+
+// Add adds a scalar value to values. Floats are formatted with the shortest
+// representation that preserves their precision, and types implementing fmt.Stringer
+// are formatted with their String method.
+func Add[T Scalar](values QueryValues, key string, value *T, zero ZeroPolicy) {
+	if value == nil {
+		addNil(values, key, zero)
+		return
+	}
+
+	var zeroValue T
+	if *value == zeroValue && zero == ZeroOmit {
+		return
+	}
+
+	values.AddValue(key, formatScalar(*value))
+}
+
+// AddTime adds a time value to values, formatted with layout as in the layout tag of
+// EncodeQuery. The zero time is a zero value.
+func AddTime(values QueryValues, key string, value *time.Time, layout string, zero ZeroPolicy) {
+	if value == nil {
+		addNil(values, key, zero)
+		return
+	}
+
+	if value.IsZero() && zero == ZeroOmit {
+		return
+	}
+
+	values.AddValue(key, formatQueryTime(*value, layout))
+}
+
+// AddStringer adds the String of value to values. A nil value, or a nil pointer, is a
+// nil value and an empty String is a zero value.
+func AddStringer(values QueryValues, key string, value fmt.Stringer, zero ZeroPolicy) {
+	if value == nil {
+		addNil(values, key, zero)
+		return
+	}
+
+	if reflectValue := reflect.ValueOf(value); reflectValue.Kind() == reflect.Pointer && reflectValue.IsNil() {
+		addNil(values, key, zero)
+		return
+	}
+
+	formatted := value.String()
+	if formatted == "" && zero == ZeroOmit {
+		return
+	}
+
+	values.AddValue(key, formatted)
+}
+
+// AddSlice adds the elements of value to values as an array with the given style. A nil
+// slice is a nil value and an empty slice is a zero value, added as an empty value.
+func AddSlice[T Scalar](values QueryValues, key string, value []T, style QueryStyle, explode bool, zero ZeroPolicy) {
+	if value == nil {
+		addNil(values, key, zero)
+		return
+	}
+
+	if len(value) == 0 {
+		if zero != ZeroOmit {
+			values.AddValue(key, "")
+		}
+		return
+	}
+
+	elements := make([]string, 0, len(value))
+	for _, element := range value {
+		elements = append(elements, formatScalar(element))
+	}
+
+	addQueryArray(values.AddValue, key, elements, style, explode)
+}
+
+func formatScalar[T Scalar](value T) string {
+	if stringer, ok := any(value).(fmt.Stringer); ok {
+		return stringer.String()
+	}
+
+	// Scalar kinds are always supported
+	formatted, _, _ := formatQueryKind(reflect.ValueOf(value))
+
+	return formatted
+}
+
+func addNil(values QueryValues, key string, zero ZeroPolicy) {
+	if zero == ZeroInclude {
+		values.AddValue(key, "")
+	}
+}
@@ -0,0 +1,123 @@
+package restclientgo
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestAdd(t *testing.T) {
+	var (
+		big      = 12345678901234567890.0
+		tiny     = 0.000000123
+		id       = int64(9007199254740993)
+		count    = uint(0)
+		empty    = ""
+		status   = queryStatus(1)
+		nilFloat *float64
+	)
+
+	tests := []struct {
+		name string
+		add  func(values QueryValues)
+		want string
+	}{
+		{
+			name: "lossless floats",
+			add: func(values QueryValues) {
+				Add(values, "big", &big, ZeroOmitNil)
+				Add(values, "tiny", &tiny, ZeroOmitNil)
+			},
+			want: "big=12345678901234567000&tiny=0.000000123",
+		},
+		{
+			name: "int64 and stringer",
+			add: func(values QueryValues) {
+				Add(values, "id", &id, ZeroOmitNil)
+				Add(values, "status", &status, ZeroOmitNil)
+			},
+			want: "id=9007199254740993&status=closed",
+		},
+		{
+			name: "omit nil",
+			add: func(values QueryValues) {
+				Add(values, "count", &count, ZeroOmitNil)
+				Add(values, "q", &empty, ZeroOmitNil)
+				Add(values, "ratio", nilFloat, ZeroOmitNil)
+			},
+			want: "count=0&q=",
+		},
+		{
+			name: "omit",
+			add: func(values QueryValues) {
+				Add(values, "count", &count, ZeroOmit)
+				Add(values, "q", &empty, ZeroOmit)
+				Add(values, "ratio", nilFloat, ZeroOmit)
+			},
+			want: "",
+		},
+		{
+			name: "include",
+			add: func(values QueryValues) {
+				Add(values, "count", &count, ZeroInclude)
+				Add(values, "ratio", nilFloat, ZeroInclude)
+			},
+			want: "count=0&ratio=",
+		},
+		{
+			name: "time",
+			add: func(values QueryValues) {
+				since := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+				AddTime(values, "since", &since, "DateOnly", ZeroOmit)
+				AddTime(values, "until", &time.Time{}, "", ZeroOmit)
+			},
+			want: "since=2024-03-01",
+		},
+		{
+			name: "stringer",
+			add: func(values QueryValues) {
+				AddStringer(values, "ip", net.IPv4(10, 0, 0, 1), ZeroOmit)
+				AddStringer(values, "nil", (*time.Location)(nil), ZeroOmit)
+			},
+			want: "ip=10.0.0.1",
+		},
+		{
+			name: "slice",
+			add: func(values QueryValues) {
+				AddSlice(values, "ids", []uint16{1, 2}, QueryStyleForm, false, ZeroOmit)
+				AddSlice(values, "tags", []string{}, QueryStyleForm, true, ZeroOmit)
+				AddSlice(values, "empty", []string{}, QueryStyleForm, true, ZeroInclude)
+			},
+			want: "empty=&ids=1%2C2",
+		},
+		{
+			name: "slice omit nil",
+			add: func(values QueryValues) {
+				AddSlice(values, "tags", []string{}, QueryStyleBrackets, true, ZeroOmitNil)
+				AddSlice(values, "ids", []int(nil), QueryStyleForm, true, ZeroOmitNil)
+			},
+			want: "tags=",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := NewURLValues()
+			tt.add(values)
+
+			if got := values.Encode(); got != tt.want {
+				t.Errorf("Add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLValues_AddFloat(t *testing.T) {
+	value := 1e-9
+
+	values := NewURLValues()
+	values.AddFloat("value", &value)
+
+	if got := values.Encode(); got != "value=0.000000001" {
+		t.Errorf("URLValues.AddFloat() = %v, want %v", got, "value=0.000000001")
+	}
+}
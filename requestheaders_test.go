@@ -0,0 +1,92 @@
+package restclientgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type headersEchoRequest struct {
+	echoRequest
+	headers    http.Header
+	headersErr error
+	trailers   http.Header
+	host       string
+}
+
+func (r *headersEchoRequest) RequestHeaders() (http.Header, error)  { return r.headers, r.headersErr }
+func (r *headersEchoRequest) RequestTrailers() (http.Header, error) { return r.trailers, nil }
+func (r *headersEchoRequest) RequestHost() string                   { return r.host }
+
+func TestRestClient_RequestHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintf(w, "%s|%s|%s|%s|%s|%s", req.Host, req.Header.Get("Idempotency-Key"), req.Header.Get("Content-Type"),
+			req.Header.Get("X-Modified"), req.Trailer.Get("X-Checksum"), body)
+	}))
+	defer server.Close()
+
+	restClient := New(server.URL).WithRequestModifier(func(req *http.Request) *http.Request {
+		// the middleware sees the request headers
+		req.Header.Set("X-Modified", req.Header.Get("Idempotency-Key"))
+		if values := req.Header.Values("Idempotency-Key"); len(values) > 0 {
+			values[0] = strings.ToUpper(values[0])
+		}
+		return req
+	})
+
+	request := &headersEchoRequest{
+		echoRequest: echoRequest{body: "payload"},
+		headers:     http.Header{"idempotency-key": {"key-1"}, "content-type": {"application/custom"}},
+		trailers:    http.Header{"x-checksum": {"sum"}},
+		host:        "api.internal",
+	}
+
+	response := &EchoResponse{}
+	if err := restClient.Post(context.Background(), request, response); err != nil {
+		t.Fatalf("RestClient.Post() error = %v", err)
+	}
+
+	want := "api.internal|KEY-1|application/custom|key-1|sum|payload"
+	if response.Body != want {
+		t.Errorf("RestClient.Post() = %v, want %v", response.Body, want)
+	}
+
+	// the request modifier does not write into the request headers
+	if got := request.headers["idempotency-key"][0]; got != "key-1" {
+		t.Errorf("RestClient.Post() request header = %v, want %v", got, "key-1")
+	}
+
+	errHeaders := errors.New("no idempotency key")
+	request.headersErr = errHeaders
+	err := restClient.Post(context.Background(), request, &EchoResponse{})
+	if !errors.Is(err, ErrRequestEncode) || !errors.Is(err, errHeaders) {
+		t.Errorf("RestClient.Post() error = %v, want %v", err, errHeaders)
+	}
+}
+
+func TestRestClient_WrappedRequestHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprintf(w, "%s %s", req.Header.Get("X-Tenant"), req.Header.Get("Range"))
+	}))
+	defer server.Close()
+
+	request := &headersEchoRequest{headers: http.Header{"X-Tenant": {"acme"}}}
+
+	response := &EchoResponse{}
+	err := New(server.URL).Get(context.Background(), &rangeRequest{Request: request, start: 10, end: -1}, response)
+	if err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	if !strings.HasPrefix(response.Body, "acme bytes=10-") {
+		t.Errorf("RestClient.Get() = %v, want %v", response.Body, "acme bytes=10-")
+	}
+}
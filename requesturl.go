@@ -0,0 +1,62 @@
+package restclientgo
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// requestURL joins the client endpoint and a request path. Slashes between the two are
+// normalized and the query parameters of both are kept, endpoint ones first. Request
+// paths that would change the scheme, host or userinfo of the endpoint are rejected,
+// while absolute URLs sharing the endpoint origin are used as they are.
+func (r *RestClient) requestURL(requestPath string) (string, error) {
+	endpointURL, err := url.Parse(r.endpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrEndpoint, err)
+	}
+
+	pathURL, err := url.Parse(requestPath)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrRequestPath, err)
+	}
+
+	if pathURL.IsAbs() {
+		if !sameOrigin(endpointURL, pathURL) {
+			return "", fmt.Errorf("%w: %s does not match endpoint origin", ErrRequestPath, requestPath)
+		}
+		return pathURL.String(), nil
+	}
+
+	if pathURL.Host != "" || pathURL.User != nil {
+		return "", fmt.Errorf("%w: %s changes the endpoint host", ErrRequestPath, requestPath)
+	}
+
+	joined := *endpointURL
+	joined.Fragment = ""
+	joined.RawFragment = ""
+	if pathURL.Path != "" {
+		joined.Path = joinPath(endpointURL.Path, pathURL.Path)
+		joined.RawPath = joinPath(endpointURL.EscapedPath(), pathURL.EscapedPath())
+	}
+
+	switch {
+	case endpointURL.RawQuery == "":
+		joined.RawQuery = pathURL.RawQuery
+	case pathURL.RawQuery != "":
+		joined.RawQuery = endpointURL.RawQuery + "&" + pathURL.RawQuery
+	}
+
+	return joined.String(), nil
+}
+
+// joinPath joins two paths with exactly one slash between them.
+func joinPath(base, path string) string {
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+func sameOrigin(a, b *url.URL) bool {
+	return strings.EqualFold(a.Scheme, b.Scheme) &&
+		strings.EqualFold(a.Host, b.Host) &&
+		a.User.String() == b.User.String()
+}
@@ -0,0 +1,121 @@
+package restclientgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRestClient_requestURL(t *testing.T) {
+	tests := []struct {
+		name        string
+		endpoint    string
+		requestPath string
+		want        string
+		wantErr     error
+	}{
+		{
+			name:        "simple",
+			endpoint:    "https://example.com",
+			requestPath: "/todos/1",
+			want:        "https://example.com/todos/1",
+		},
+		{
+			name:        "double slash",
+			endpoint:    "https://example.com/api/",
+			requestPath: "/todos/1",
+			want:        "https://example.com/api/todos/1",
+		},
+		{
+			name:        "missing slash",
+			endpoint:    "https://example.com/api",
+			requestPath: "todos/1",
+			want:        "https://example.com/api/todos/1",
+		},
+		{
+			name:        "trailing slash kept",
+			endpoint:    "https://example.com/api",
+			requestPath: "/todos/",
+			want:        "https://example.com/api/todos/",
+		},
+		{
+			name:        "empty path",
+			endpoint:    "https://example.com/api/",
+			requestPath: "",
+			want:        "https://example.com/api/",
+		},
+		{
+			name:        "escaped path",
+			endpoint:    "https://example.com/files%2Fv1",
+			requestPath: "/a%2Fb",
+			want:        "https://example.com/files%2Fv1/a%2Fb",
+		},
+		{
+			name:        "merged query",
+			endpoint:    "https://example.com/api?key=secret",
+			requestPath: "/todos?userId=1&page=2",
+			want:        "https://example.com/api/todos?key=secret&userId=1&page=2",
+		},
+		{
+			name:        "query only",
+			endpoint:    "https://example.com/api",
+			requestPath: "?page=2",
+			want:        "https://example.com/api?page=2",
+		},
+		{
+			name:        "same origin absolute URL",
+			endpoint:    "https://example.com/api",
+			requestPath: "https://EXAMPLE.com/api/todos?cursor=abc",
+			want:        "https://EXAMPLE.com/api/todos?cursor=abc",
+		},
+		{
+			name:        "userinfo in path",
+			endpoint:    "https://example.com",
+			requestPath: "@evil.com/x",
+			want:        "https://example.com/@evil.com/x",
+		},
+		{
+			name:        "scheme relative",
+			endpoint:    "https://example.com",
+			requestPath: "//evil.com/x",
+			wantErr:     ErrRequestPath,
+		},
+		{
+			name:        "other host",
+			endpoint:    "https://example.com",
+			requestPath: "https://evil.com/x",
+			wantErr:     ErrRequestPath,
+		},
+		{
+			name:        "other scheme",
+			endpoint:    "https://example.com",
+			requestPath: "http://example.com/x",
+			wantErr:     ErrRequestPath,
+		},
+		{
+			name:        "other userinfo",
+			endpoint:    "https://example.com",
+			requestPath: "https://user@example.com/x",
+			wantErr:     ErrRequestPath,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.endpoint).requestURL(tt.requestPath)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RestClient.requestURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("RestClient.requestURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestClient_GetRejectsHostChange(t *testing.T) {
+	err := New("https://example.com").Get(context.Background(), &pageRequest{Request: &todoRequest{}, path: "//evil.com/todos"}, &TodoResponse{})
+	if !errors.Is(err, ErrRequestPath) {
+		t.Errorf("RestClient.Get() error = %v, want %v", err, ErrRequestPath)
+	}
+}
@@ -0,0 +1,109 @@
+package restclientgo
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type decodeOnlyResponse struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+func (r *decodeOnlyResponse) Decode(body io.Reader) error {
+	return json.NewDecoder(body).Decode(r)
+}
+
+type capabilitiesResponse struct {
+	decodeOnlyResponse
+	trailers Headers
+	tlsState *tls.ConnectionState
+	timing   Timing
+}
+
+func (r *capabilitiesResponse) SetTrailers(trailers Headers) error {
+	r.trailers = trailers
+	return nil
+}
+
+func (r *capabilitiesResponse) SetTLSState(state *tls.ConnectionState) error {
+	r.tlsState = state
+	return nil
+}
+
+func (r *capabilitiesResponse) SetTiming(timing Timing) error {
+	r.timing = timing
+	return nil
+}
+
+func newCapabilitiesServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/2") {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Trailer", "X-Checksum")
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`{"id":1,"title":"capabilities"}`))
+		w.Header().Set("X-Checksum", "abc")
+	}))
+}
+
+func TestRestClient_DecoderOnly(t *testing.T) {
+	server := newCapabilitiesServer()
+	defer server.Close()
+
+	restClient := New(server.URL).WithHTTPClient(server.Client())
+
+	// without ContentTypeAcceptor the body is decoded whatever its content type
+	response := &decodeOnlyResponse{}
+	if err := restClient.Get(context.Background(), &todoRequest{ID: "1"}, response); err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	if *response != (decodeOnlyResponse{ID: 1, Title: "capabilities"}) {
+		t.Errorf("RestClient.Get() = %+v", response)
+	}
+
+	// without StatusCodeSetter error statuses are returned as errors
+	err := restClient.Get(context.Background(), &todoRequest{ID: "2"}, &decodeOnlyResponse{})
+	if !errors.Is(err, ErrResponseStatus) {
+		t.Errorf("RestClient.Get() error = %v, want %v", err, ErrResponseStatus)
+	}
+}
+
+func TestRestClient_OptionalCapabilities(t *testing.T) {
+	server := newCapabilitiesServer()
+	defer server.Close()
+
+	restClient := New(server.URL).WithHTTPClient(server.Client())
+
+	response := &capabilitiesResponse{}
+	if err := restClient.Get(context.Background(), &todoRequest{ID: "1"}, response); err != nil {
+		t.Fatalf("RestClient.Get() error = %v", err)
+	}
+
+	if response.Title != "capabilities" {
+		t.Errorf("RestClient.Get() = %+v", response.decodeOnlyResponse)
+	}
+
+	if got := response.trailers["X-Checksum"]; len(got) != 1 || got[0] != "abc" {
+		t.Errorf("RestClient.Get() trailers = %v", response.trailers)
+	}
+
+	if response.tlsState == nil || !response.tlsState.HandshakeComplete {
+		t.Errorf("RestClient.Get() TLS state = %v", response.tlsState)
+	}
+
+	timing := response.timing
+	if timing.Start.IsZero() || timing.TLSHandshake <= 0 || timing.TimeToFirstByte <= 0 || timing.Total < timing.TimeToFirstByte {
+		t.Errorf("RestClient.Get() timing = %+v", timing)
+	}
+}
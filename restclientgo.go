@@ -3,10 +3,13 @@ package restclientgo
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 )
 
 const maxStreamBufferSize = 512 * 1024
@@ -16,8 +19,20 @@ type StreamCallback func([]byte) error
 type RestClient struct {
 	httpClient         *http.Client
 	endpoint           string
+	rawEndpoint        string
+	endpointErr        error
+	endpointTransport  http.RoundTripper
+	schemeClient       *http.Client
+	schemeClientErr    error
 	requestModifier    func(*http.Request) *http.Request
 	forceDecodeOnError bool
+	callOptions        []CallOption
+	progressCallback   ProgressCallback
+	progressInterval   time.Duration
+
+	requestEncoding      string
+	compressionThreshold int
+	skipDecompression    bool
 }
 
 type Error string
@@ -32,16 +47,16 @@ const (
 	ErrRequestEncode  = Error("invalid request encode")
 	ErrHTTPRequest    = Error("invalid http request")
 	ErrResponseDecode = Error("invalid response decode")
-)
-
-type httpMethod string
-
-const (
-	methodGet    httpMethod = "GET"
-	methodPost   httpMethod = "POST"
-	methodDelete httpMethod = "DELETE"
-	methodPut    httpMethod = "PUT"
-	methodPatch  httpMethod = "PATCH"
+	ErrPagination     = Error("invalid pagination")
+	ErrMaxPages       = Error("pagination max pages exceeded")
+	ErrDownload       = Error("invalid download")
+	ErrEndpoint       = Error("invalid endpoint")
+	ErrPathTemplate   = Error("invalid path template")
+	ErrQueryEncode    = Error("invalid query encode")
+	ErrQueryDecode    = Error("invalid query decode")
+	ErrAutoRequest    = Error("invalid auto request")
+	ErrResponseStatus = Error("unexpected response status")
+	ErrResponseSize   = Error("response size limit exceeded")
 )
 
 type Headers map[string][]string
@@ -54,36 +69,162 @@ type Request interface {
 	ContentType() string
 }
 
-type Response interface {
+// Decoder is the only interface required of responses. The other capabilities of a
+// response are discovered through the optional interfaces it implements.
+type Decoder interface {
 	// Decode decodes the response body into the given interface if the
 	// response matches the AcceptContentType.
 	Decode(body io.Reader) error
+}
+
+type BodySetter interface {
 	// SetBody sets the response raw body if the response can't be decoded.
 	SetBody(body io.Reader) error
+}
+
+type ContentTypeAcceptor interface {
 	// AcceptContentType returns the content type that the response should be decoded to.
 	AcceptContentType() string
+}
+
+type StatusCodeSetter interface {
 	// SetStatusCode sets the HTTP response status code.
 	SetStatusCode(code int) error
+}
+
+type HeadersSetter interface {
 	// SetHeaders sets the HTTP response headers.
 	SetHeaders(headers Headers) error
 }
 
+// Response is a response implementing all the base capabilities.
+type Response interface {
+	Decoder
+	BodySetter
+	ContentTypeAcceptor
+	StatusCodeSetter
+	HeadersSetter
+}
+
+type TrailersSetter interface {
+	// SetTrailers sets the HTTP response trailers. The body is read to the end
+	// before trailers are set.
+	SetTrailers(trailers Headers) error
+}
+
+type TLSStateSetter interface {
+	// SetTLSState sets the TLS connection state of HTTPS responses.
+	SetTLSState(state *tls.ConnectionState) error
+}
+
+type TimingSetter interface {
+	// SetTiming sets the timing of the request, once the response is handled.
+	SetTiming(timing Timing) error
+}
+
+// responseUnwrapper is implemented by responses wrapping another one, so that the
+// capabilities the wrapper does not implement are discovered on the wrapped response.
+type responseUnwrapper interface {
+	unwrapResponse() Decoder
+}
+
+// responseAs returns the first response of the wrapping chain implementing T.
+func responseAs[T any](response Decoder) (T, bool) {
+	for {
+		if capability, ok := response.(T); ok {
+			return capability, true
+		}
+
+		unwrapper, ok := response.(responseUnwrapper)
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		response = unwrapper.unwrapResponse()
+	}
+}
+
 type Streamable interface {
 	// StreamCallback get the stream callback if any.
 	StreamCallback() StreamCallback
 }
 
-// New creates a new RestClient.
+type Sizable interface {
+	// ContentLength returns the size of the encoded request body, or -1 if unknown.
+	ContentLength() int64
+}
+
+type HeadersRequest interface {
+	// RequestHeaders returns the HTTP headers of the request. They are set after the
+	// Content-Type and before the request modifier.
+	RequestHeaders() (http.Header, error)
+}
+
+type TrailersRequest interface {
+	// RequestTrailers returns the HTTP trailers sent after the request body. Values
+	// can be added to the returned header while the body is read, and the body is
+	// sent with chunked encoding.
+	RequestTrailers() (http.Header, error)
+}
+
+type HostRequest interface {
+	// RequestHost returns the Host header of the request, overriding the endpoint host
+	// if not empty.
+	RequestHost() string
+}
+
+// requestUnwrapper is implemented by requests wrapping another one, so that the
+// capabilities the wrapper does not implement are discovered on the wrapped request.
+type requestUnwrapper interface {
+	unwrapRequest() Request
+}
+
+// requestAs returns the first request of the wrapping chain implementing T.
+func requestAs[T any](request Request) (T, bool) {
+	for {
+		if capability, ok := request.(T); ok {
+			return capability, true
+		}
+
+		unwrapper, ok := request.(requestUnwrapper)
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		request = unwrapper.unwrapRequest()
+	}
+}
+
+// wrappedRequestHeaders returns a copy of the headers of a request wrapped by another one.
+func wrappedRequestHeaders(request Request) (http.Header, error) {
+	headersRequest, hasHeaders := requestAs[HeadersRequest](request)
+	if !hasHeaders {
+		return make(http.Header), nil
+	}
+
+	header, err := headersRequest.RequestHeaders()
+	if err != nil || header == nil {
+		return make(http.Header), err
+	}
+
+	return header.Clone(), nil
+}
+
+// New creates a new RestClient. Besides HTTP URLs, the endpoint can use any scheme
+// registered with RegisterEndpointScheme, such as unix:///var/run/app.sock.
 func New(endpoint string) *RestClient {
-	return &RestClient{
-		endpoint:   endpoint,
+	restClient := &RestClient{
 		httpClient: &http.Client{},
 	}
+	restClient.resolveEndpoint(endpoint)
+
+	return restClient
 }
 
 // SetHTTPClient overrides the default http client.
 func (r *RestClient) SetHTTPClient(client *http.Client) {
 	r.httpClient = client
+	r.applyEndpointTransport()
 }
 
 // SetRequestModifier adds a function that will modify each request
@@ -100,6 +241,7 @@ func (r *RestClient) WithRequestModifier(requestModifier func(*http.Request) *ht
 // WithHTTPClient overrides the default http client.
 func (r *RestClient) WithHTTPClient(client *http.Client) *RestClient {
 	r.httpClient = client
+	r.applyEndpointTransport()
 	return r
 }
 
@@ -110,109 +252,361 @@ func (r *RestClient) WithDecodeOnError(decodeOnError bool) *RestClient {
 }
 
 func (r *RestClient) SetEndpoint(endpoint string) {
-	r.endpoint = endpoint
+	r.resolveEndpoint(endpoint)
 }
 
 func (r *RestClient) Endpoint() string {
+	if r.rawEndpoint != "" {
+		return r.rawEndpoint
+	}
+
 	return r.endpoint
 }
 
 // Get performs a GET request.
-func (r *RestClient) Get(ctx context.Context, request Request, response Response) error {
-	return r.do(ctx, methodGet, request, response)
+func (r *RestClient) Get(ctx context.Context, request Request, response Decoder, opts ...CallOption) error {
+	return r.do(ctx, http.MethodGet, request, response, opts...)
 }
 
 // Post performs a POST request.
-func (r *RestClient) Post(ctx context.Context, request Request, response Response) error {
-	return r.do(ctx, methodPost, request, response)
+func (r *RestClient) Post(ctx context.Context, request Request, response Decoder, opts ...CallOption) error {
+	return r.do(ctx, http.MethodPost, request, response, opts...)
 }
 
 // Delete performs a DELETE request.
-func (r *RestClient) Delete(ctx context.Context, request Request, response Response) error {
-	return r.do(ctx, methodDelete, request, response)
+func (r *RestClient) Delete(ctx context.Context, request Request, response Decoder, opts ...CallOption) error {
+	return r.do(ctx, http.MethodDelete, request, response, opts...)
 }
 
 // Put performs a PUT request.
-func (r *RestClient) Put(ctx context.Context, request Request, response Response) error {
-	return r.do(ctx, methodPut, request, response)
+func (r *RestClient) Put(ctx context.Context, request Request, response Decoder, opts ...CallOption) error {
+	return r.do(ctx, http.MethodPut, request, response, opts...)
 }
 
 // Patch performs a PATCH request.
-func (r *RestClient) Patch(ctx context.Context, request Request, response Response) error {
-	return r.do(ctx, methodPatch, request, response)
+func (r *RestClient) Patch(ctx context.Context, request Request, response Decoder, opts ...CallOption) error {
+	return r.do(ctx, http.MethodPatch, request, response, opts...)
+}
+
+// Head performs a HEAD request. The response gets the status code and the headers,
+// its body is not decoded.
+func (r *RestClient) Head(ctx context.Context, request Request, response Decoder, opts ...CallOption) error {
+	return r.do(ctx, http.MethodHead, request, response, opts...)
 }
 
+// Options performs an OPTIONS request.
+func (r *RestClient) Options(ctx context.Context, request Request, response Decoder, opts ...CallOption) error {
+	return r.do(ctx, http.MethodOptions, request, response, opts...)
+}
+
+// Do performs a request with any method, such as the PROPFIND or MKCOL of WebDAV.
+func (r *RestClient) Do(ctx context.Context, method string, request Request, response Decoder, opts ...CallOption) error {
+	return r.do(ctx, method, request, response, opts...)
+}
+
+func (r *RestClient) do(ctx context.Context, method string, request Request, response Decoder, opts ...CallOption) error {
+	httpClient, err := r.client()
+	if err != nil {
+		return err
+	}
+
+	options := r.newCallOptions(request, opts)
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	timingSetter, hasTiming := responseAs[TimingSetter](response)
+	var timing *timingTrace
+
+	var httpResponse *http.Response
+	for attempt := 1; ; attempt++ {
+		attemptCtx := ctx
+		if hasTiming {
+			timing = newTimingTrace()
+			attemptCtx = timing.withTrace(ctx)
+		}
+
+		httpRequest, err := r.BuildHTTPRequest(attemptCtx, method, request)
+		if err != nil {
+			return err
+		}
+
+		if options.dryRun != nil {
+			dryRunResult, err := newDryRunResult(httpRequest)
+			if err != nil {
+				return err
+			}
+			return options.dryRun(dryRunResult)
+		}
+
+		progressCallback := r.progressCallbackFor(request)
+		if progressCallback != nil {
+			r.trackRequestProgress(httpRequest, progressCallback)
+		}
+
+		httpResponse, err = httpClient.Do(httpRequest)
+
+		if delay, retry := options.retryPolicy.retryDelay(attempt, httpRequest, httpResponse, err); retry {
+			if httpResponse != nil {
+				_, _ = io.Copy(io.Discard, io.LimitReader(httpResponse.Body, maxRetryDrainSize))
+				httpResponse.Body.Close()
+			}
+
+			if err := sleep(ctx, delay); err != nil {
+				return fmt.Errorf("%w: %w", ErrHTTPRequest, err)
+			}
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrHTTPRequest, err)
+		}
+
+		if progressCallback != nil {
+			r.trackResponseProgress(httpResponse, progressCallback)
+		}
+
+		break
+	}
+
+	err = r.handleHTTPResponse(method, httpResponse, response, options)
+	if err != nil {
+		return err
+	}
+
+	if hasTiming {
+		return timingSetter.SetTiming(timing.timing())
+	}
+
+	return nil
+}
+
+// BuildHTTPRequest builds the HTTP request sent for request, after the request modifier,
+// without sending it. Together with HandleHTTPResponse it allows sending requests with
+// any transport.
+func (r *RestClient) BuildHTTPRequest(ctx context.Context, method string, request Request) (*http.Request, error) {
+	if r.endpointErr != nil {
+		return nil, r.endpointErr
+	}
+
+	httpRequest, err := r.buildRequest(method, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpRequest.WithContext(ctx), nil
+}
+
+// HandleHTTPResponse passes httpResponse, the response of a method call sending request,
+// to response as the calls of the client do, and closes its body. request may be nil; when
+// it is an OptionsRequest its options apply before opts. Of the call options only
+// WithDecodeOnError, WithExpectedStatus and WithMaxResponseSize apply, and the
+// TimingSetter of response is not called since the request was not sent by the client.
+func (r *RestClient) HandleHTTPResponse(method string, request Request, httpResponse *http.Response, response Decoder, opts ...CallOption) error {
+	return r.handleHTTPResponse(method, httpResponse, response, r.newCallOptions(request, opts))
+}
+
+func (r *RestClient) handleHTTPResponse(
+	method string,
+	httpResponse *http.Response,
+	response Decoder,
+	options *callOptions,
+) error {
+	if !r.skipDecompression {
+		decompressResponse(httpResponse)
+	}
+	defer httpResponse.Body.Close()
+
+	if options.maxResponseSize > 0 {
+		err := limitResponseSize(httpResponse, options.maxResponseSize)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.handleResponse(method, httpResponse, response, options)
+}
+
+// buildRequest builds the HTTP request of request, applying the request modifier.
+//
 //nolint:gocognit
-func (r *RestClient) do(ctx context.Context, method httpMethod, request Request, response Response) error {
+func (r *RestClient) buildRequest(method string, request Request) (*http.Request, error) {
 	requestPath, err := request.Path()
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrRequestPath, err)
+		return nil, fmt.Errorf("%w: %w", ErrRequestPath, err)
+	}
+
+	requestURL, err := r.requestURL(requestPath)
+	if err != nil {
+		return nil, err
 	}
 
 	requestEncodedBody, err := request.Encode()
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrRequestEncode, err)
+		return nil, fmt.Errorf("%w: %w", ErrRequestEncode, err)
 	}
 
-	requestURL := r.endpoint + requestPath
-	httpRequest, err := http.NewRequest(string(method), requestURL, requestEncodedBody)
+	requestEncodedBody, compressed, err := r.compressRequestBody(requestEncodedBody)
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrHTTPRequest, err)
+		return nil, fmt.Errorf("%w: %w", ErrRequestEncode, err)
+	}
+
+	httpRequest, err := http.NewRequest(method, requestURL, requestEncodedBody)
+	if err != nil {
+		closeBody(requestEncodedBody)
+		return nil, fmt.Errorf("%w: %w", ErrHTTPRequest, err)
+	}
+
+	if compressed {
+		httpRequest.Header.Set("Content-Encoding", r.requestEncoding)
+	} else if sizable, isSizable := requestAs[Sizable](request); isSizable && sizable.ContentLength() >= 0 {
+		httpRequest.ContentLength = sizable.ContentLength()
 	}
 
 	if request.ContentType() != "" {
 		httpRequest.Header.Set("Content-Type", request.ContentType())
 	}
 
-	if r.requestModifier != nil {
-		httpRequest = r.requestModifier(httpRequest)
+	if headersRequest, hasHeaders := requestAs[HeadersRequest](request); hasHeaders {
+		requestHeaders, err := headersRequest.RequestHeaders()
+		if err != nil {
+			closeBody(requestEncodedBody)
+			return nil, fmt.Errorf("%w: %w", ErrRequestEncode, err)
+		}
+		for key, values := range requestHeaders {
+			httpRequest.Header[http.CanonicalHeaderKey(key)] = slices.Clone(values)
+		}
 	}
 
-	httpRequest = httpRequest.WithContext(ctx)
-
-	httpResponse, err := r.httpClient.Do(httpRequest)
-	if err != nil {
-		return fmt.Errorf("%w: %w", ErrHTTPRequest, err)
+	if trailersRequest, hasTrailers := requestAs[TrailersRequest](request); hasTrailers {
+		requestTrailers, err := trailersRequest.RequestTrailers()
+		if err != nil {
+			closeBody(requestEncodedBody)
+			return nil, fmt.Errorf("%w: %w", ErrRequestEncode, err)
+		}
+		if len(requestTrailers) > 0 && httpRequest.Body != nil {
+			httpRequest.Trailer = make(http.Header, len(requestTrailers))
+			for key, values := range requestTrailers {
+				httpRequest.Trailer[http.CanonicalHeaderKey(key)] = slices.Clone(values)
+			}
+			// trailers are only sent with chunked encoding
+			httpRequest.ContentLength = -1
+		}
 	}
-	defer httpResponse.Body.Close()
 
-	var headers = make(Headers)
-	for k, v := range httpResponse.Header {
-		headers[k] = v
+	if hostRequest, hasHost := requestAs[HostRequest](request); hasHost && hostRequest.RequestHost() != "" {
+		httpRequest.Host = hostRequest.RequestHost()
 	}
 
-	err = response.SetHeaders(headers)
-	if err != nil {
-		return err
+	if r.requestModifier != nil {
+		httpRequest = r.requestModifier(httpRequest)
 	}
 
-	err = response.SetStatusCode(httpResponse.StatusCode)
-	if err != nil {
-		return err
+	return httpRequest, nil
+}
+
+// handleResponse passes the response to the capabilities implemented by response.
+//
+//nolint:gocognit
+func (r *RestClient) handleResponse(
+	method string,
+	httpResponse *http.Response,
+	response Decoder,
+	options *callOptions,
+) error {
+	if headersSetter, ok := responseAs[HeadersSetter](response); ok {
+		var headers = make(Headers)
+		for k, v := range httpResponse.Header {
+			headers[k] = v
+		}
+
+		err := headersSetter.SetHeaders(headers)
+		if err != nil {
+			return err
+		}
 	}
 
-	if httpResponse.StatusCode >= 400 && !r.forceDecodeOnError {
-		err = response.SetBody(httpResponse.Body)
+	statusCodeSetter, hasStatusCode := responseAs[StatusCodeSetter](response)
+	if hasStatusCode {
+		err := statusCodeSetter.SetStatusCode(httpResponse.StatusCode)
 		if err != nil {
 			return err
 		}
-		return nil
 	}
 
-	if response.AcceptContentType() == "" {
-		err = response.SetBody(httpResponse.Body)
+	if tlsStateSetter, ok := responseAs[TLSStateSetter](response); ok && httpResponse.TLS != nil {
+		err := tlsStateSetter.SetTLSState(httpResponse.TLS)
 		if err != nil {
 			return err
 		}
-		return nil
 	}
 
-	err = matchContentType(httpResponse, response)
+	err := r.handleResponseBody(method, httpResponse, response, hasStatusCode, options)
 	if err != nil {
 		return err
 	}
 
-	if streamable, isStreamable := response.(Streamable); isStreamable && streamable.StreamCallback() != nil {
+	if trailersSetter, ok := responseAs[TrailersSetter](response); ok {
+		// trailers are only available once the body is read
+		_, _ = io.Copy(io.Discard, httpResponse.Body)
+
+		var trailers = make(Headers)
+		for k, v := range httpResponse.Trailer {
+			trailers[k] = v
+		}
+
+		return trailersSetter.SetTrailers(trailers)
+	}
+
+	return nil
+}
+
+func (r *RestClient) handleResponseBody(
+	method string,
+	httpResponse *http.Response,
+	response Decoder,
+	hasStatusCode bool,
+	options *callOptions,
+) error {
+	bodySetter, hasBody := responseAs[BodySetter](response)
+
+	if len(options.expectedStatuses) > 0 {
+		if !slices.Contains(options.expectedStatuses, httpResponse.StatusCode) {
+			return fmt.Errorf("%w: %s", ErrResponseStatus, httpResponse.Status)
+		}
+	} else if httpResponse.StatusCode >= 400 && !options.decodeOnError {
+		if hasBody && method != http.MethodHead {
+			return bodySetter.SetBody(httpResponse.Body)
+		}
+		if !hasStatusCode {
+			return fmt.Errorf("%w: %s", ErrResponseStatus, httpResponse.Status)
+		}
+		return nil
+	}
+
+	// HEAD responses have no body to match and decode
+	if method == http.MethodHead {
+		return nil
+	}
+
+	contentTypeAcceptor, hasContentType := responseAs[ContentTypeAcceptor](response)
+	if hasContentType {
+		if contentTypeAcceptor.AcceptContentType() == "" && hasBody {
+			return bodySetter.SetBody(httpResponse.Body)
+		}
+
+		if contentTypeAcceptor.AcceptContentType() != "" {
+			err := matchContentType(httpResponse, contentTypeAcceptor)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var err error
+	if streamable, isStreamable := responseAs[Streamable](response); isStreamable && streamable.StreamCallback() != nil {
 		err = stream(streamable.StreamCallback(), httpResponse.Body)
 	} else {
 		err = response.Decode(httpResponse.Body)
@@ -225,7 +619,7 @@ func (r *RestClient) do(ctx context.Context, method httpMethod, request Request,
 	return nil
 }
 
-func matchContentType(httpResponse *http.Response, response Response) error {
+func matchContentType(httpResponse *http.Response, response ContentTypeAcceptor) error {
 	contentTypeToMatch := response.AcceptContentType()
 	contentType := httpResponse.Header.Get("Content-Type")
 
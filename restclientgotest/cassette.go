@@ -0,0 +1,336 @@
+package restclientgotest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const redacted = "REDACTED"
+
+// RecorderMode tells whether a Recorder records or replays interactions.
+type RecorderMode int
+
+const (
+	// ModeReplay serves responses from the cassette.
+	ModeReplay RecorderMode = iota
+	// ModeRecord forwards requests to the real transport and records them in the cassette.
+	ModeRecord
+)
+
+// Cassette holds the recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request/response pair.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a recorded HTTP request.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// RecordedResponse is a recorded HTTP response.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is a recorded body, saved as a string if it is valid UTF-8 or as base64 otherwise.
+type Body []byte
+
+// MarshalJSON encodes the body as a string, or as an object holding its base64 encoding.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON decodes a body encoded by MarshalJSON.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+
+	var encoded map[string]string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded["base64"])
+	*b = decoded
+	return err
+}
+
+// RequestMatcherFunc tells whether a request matches a recorded one.
+type RequestMatcherFunc func(request, recorded RecordedRequest) bool
+
+// MatchMethodAndURL matches requests with the same method and URL.
+func MatchMethodAndURL(request, recorded RecordedRequest) bool {
+	return request.Method == recorded.Method && request.URL == recorded.URL
+}
+
+// MatchMethodURLAndBody matches requests with the same method, URL and body.
+func MatchMethodURLAndBody(request, recorded RecordedRequest) bool {
+	return MatchMethodAndURL(request, recorded) && bytes.Equal(request.Body, recorded.Body)
+}
+
+// Recorder is an http.RoundTripper recording interactions with a real transport into
+// a cassette file, or replaying them from it.
+type Recorder struct {
+	mu            sync.Mutex
+	path          string
+	mode          RecorderMode
+	transport     http.RoundTripper
+	matcher       RequestMatcherFunc
+	redactHeaders []string
+	secrets       []string
+	strict        bool
+	cassette      *Cassette
+	replayed      []bool
+}
+
+// NewRecorder creates a new Recorder using the cassette file at path. In replay
+// mode the cassette is loaded from the file.
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	recorder := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		matcher:   MatchMethodURLAndBody,
+		cassette:  &Cassette{},
+	}
+
+	if mode == ModeRecord {
+		return recorder, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCassette, err)
+	}
+
+	err = json.Unmarshal(data, recorder.cassette)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCassette, err)
+	}
+	recorder.replayed = make([]bool, len(recorder.cassette.Interactions))
+
+	return recorder, nil
+}
+
+// WithTransport sets the real transport used to record interactions. It defaults
+// to http.DefaultTransport.
+func (r *Recorder) WithTransport(transport http.RoundTripper) *Recorder {
+	r.transport = transport
+	return r
+}
+
+// WithMatcher sets how requests are matched against the recorded ones. It defaults
+// to MatchMethodURLAndBody.
+func (r *Recorder) WithMatcher(matcher RequestMatcherFunc) *Recorder {
+	r.matcher = matcher
+	return r
+}
+
+// WithRedactedHeaders replaces the values of the given request and response headers
+// before saving them.
+func (r *Recorder) WithRedactedHeaders(headers ...string) *Recorder {
+	r.redactHeaders = append(r.redactHeaders, headers...)
+	return r
+}
+
+// WithRedactedSecrets replaces the occurrences of the given secrets in URLs, headers
+// and bodies before saving them.
+func (r *Recorder) WithRedactedSecrets(secrets ...string) *Recorder {
+	r.secrets = append(r.secrets, secrets...)
+	return r
+}
+
+// WithStrict makes replay fail on unrecorded requests. When not strict, unrecorded
+// requests are forwarded to the real transport and added to the cassette.
+func (r *Recorder) WithStrict(strict bool) *Recorder {
+	r.strict = strict
+	return r
+}
+
+// Client returns an http.Client using the recorder, to be installed with
+// restclientgo.RestClient.WithHTTPClient.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Cassette returns the recorded interactions.
+func (r *Recorder) Cassette() *Cassette {
+	return r.cassette
+}
+
+// RoundTrip records or replays the request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recordedRequest := r.redactRequest(RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	})
+
+	if r.mode == ModeReplay {
+		if response, found := r.replay(req, recordedRequest); found {
+			return response, nil
+		}
+
+		if r.strict {
+			return nil, fmt.Errorf("%w: %s %s", ErrUnrecordedRequest, recordedRequest.Method, recordedRequest.URL)
+		}
+	}
+
+	return r.record(req, recordedRequest)
+}
+
+// Save writes the cassette to its file.
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCassette, err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(r.path), 0o755)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCassette, err)
+	}
+
+	err = os.WriteFile(r.path, data, 0o600)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCassette, err)
+	}
+
+	return nil
+}
+
+// replay returns the response of the first matching interaction not replayed yet,
+// or of the first matching one if all of them have been replayed.
+func (r *Recorder) replay(req *http.Request, request RecordedRequest) (*http.Response, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !r.matcher(request, interaction.Request) {
+			continue
+		}
+
+		if !r.replayed[i] {
+			found = i
+			break
+		}
+
+		if found < 0 {
+			found = i
+		}
+	}
+
+	if found < 0 {
+		return nil, false
+	}
+	r.replayed[found] = true
+
+	recorded := r.cassette.Interactions[found].Response
+	return newResponse(req, recorded.StatusCode, recorded.Header.Clone(), recorded.Body), true
+}
+
+func (r *Recorder) record(req *http.Request, request RecordedRequest) (*http.Response, error) {
+	response, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction := Interaction{
+		Request: request,
+		Response: r.redactResponse(RecordedResponse{
+			StatusCode: response.StatusCode,
+			Header:     response.Header.Clone(),
+			Body:       body,
+		}),
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.replayed = append(r.replayed, true)
+	r.mu.Unlock()
+
+	return response, nil
+}
+
+func (r *Recorder) redactRequest(request RecordedRequest) RecordedRequest {
+	request.URL = r.redactSecrets(request.URL)
+	request.Header = r.redactHeader(request.Header)
+	request.Body = Body(r.redactSecrets(string(request.Body)))
+	return request
+}
+
+func (r *Recorder) redactResponse(response RecordedResponse) RecordedResponse {
+	response.Header = r.redactHeader(response.Header)
+	response.Body = Body(r.redactSecrets(string(response.Body)))
+	return response
+}
+
+func (r *Recorder) redactHeader(header http.Header) http.Header {
+	for _, key := range r.redactHeaders {
+		if header.Get(key) != "" {
+			header.Set(key, redacted)
+		}
+	}
+
+	for key, values := range header {
+		for i, value := range values {
+			header[key][i] = r.redactSecrets(value)
+		}
+	}
+
+	return header
+}
+
+func (r *Recorder) redactSecrets(s string) string {
+	for _, secret := range r.secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,124 @@
+package restclientgotest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/henomis/restclientgo"
+)
+
+func TestRecorder(t *testing.T) {
+	const secret = "s3cr3t-t0k3n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":42,"title":` + strings.TrimPrefix(string(body), `{"title":`)))
+	}))
+
+	cassettePath := filepath.Join(t.TempDir(), "fixtures", "todos.json")
+	request := &createTodoRequest{Title: "record me", UserID: 1}
+	authorize := func(req *http.Request) *http.Request {
+		req.Header.Set("Authorization", "Bearer "+secret)
+		return req
+	}
+
+	// record
+	recorder, err := NewRecorder(cassettePath, ModeRecord)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	recorder.WithRedactedHeaders("Set-Cookie").WithRedactedSecrets(secret)
+
+	restClient := restclientgo.New(server.URL).WithHTTPClient(recorder.Client()).WithRequestModifier(authorize)
+
+	recordedResponse := &TodoResponse{}
+	if err = restClient.Post(context.Background(), request, recordedResponse); err != nil {
+		t.Fatalf("RestClient.Post() error = %v", err)
+	}
+
+	if err = recorder.Save(); err != nil {
+		t.Fatalf("Recorder.Save() error = %v", err)
+	}
+	server.Close()
+
+	cassette, err := os.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(cassette), secret) || strings.Contains(string(cassette), "session=abc") {
+		t.Errorf("cassette contains secrets: %s", cassette)
+	}
+
+	// replay
+	replayer, err := NewRecorder(cassettePath, ModeReplay)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	replayer.WithRedactedSecrets(secret).WithStrict(true)
+
+	restClient.WithHTTPClient(replayer.Client())
+
+	replayedResponse := &TodoResponse{}
+	if err = restClient.Post(context.Background(), request, replayedResponse); err != nil {
+		t.Fatalf("RestClient.Post() error = %v", err)
+	}
+
+	if *replayedResponse != *recordedResponse {
+		t.Errorf("RestClient.Post() = %+v, want %+v", replayedResponse, recordedResponse)
+	}
+
+	if recordedResponse.HTTPStatusCode != http.StatusCreated || recordedResponse.ID != 42 {
+		t.Errorf("RestClient.Post() = %+v", recordedResponse)
+	}
+
+	// unrecorded request
+	err = restClient.Post(context.Background(), &createTodoRequest{Title: "other"}, &TodoResponse{})
+	if !errors.Is(err, ErrUnrecordedRequest) {
+		t.Errorf("RestClient.Post() error = %v, want %v", err, ErrUnrecordedRequest)
+	}
+}
+
+func TestRecorder_MissingCassette(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay)
+	if !errors.Is(err, ErrCassette) {
+		t.Errorf("NewRecorder() error = %v, want %v", err, ErrCassette)
+	}
+}
+
+func TestBody_JSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body Body
+	}{
+		{name: "text", body: Body(`{"id":1}`)},
+		{name: "binary", body: Body{0xff, 0x00, 0xfe}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.body.MarshalJSON()
+			if err != nil {
+				t.Fatalf("Body.MarshalJSON() error = %v", err)
+			}
+
+			var got Body
+			if err := got.UnmarshalJSON(data); err != nil {
+				t.Fatalf("Body.UnmarshalJSON() error = %v", err)
+			}
+
+			if string(got) != string(tt.body) {
+				t.Errorf("Body round trip = %v, want %v", got, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+// Package restclientgotest provides HTTP transports to test code built on restclientgo
+// without spinning up HTTP servers.
+package restclientgotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	ErrUnmatchedRequest  = Error("unmatched request")
+	ErrUnrecordedRequest = Error("unrecorded request")
+	ErrCassette          = Error("invalid cassette")
+)
+
+// TestingT is the subset of testing.TB used to report failures.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// RequestMatcher returns a description of the mismatch if the request, whose body
+// is given, does not match. It returns an empty string on match.
+type RequestMatcher func(req *http.Request, body []byte) string
+
+// MockTransport is an http.RoundTripper answering requests with the canned responses
+// of the first matching expectation.
+type MockTransport struct {
+	mu           sync.Mutex
+	expectations []*Expectation
+	unmatched    []string
+}
+
+// NewMockTransport creates a new MockTransport.
+func NewMockTransport() *MockTransport {
+	return &MockTransport{}
+}
+
+// Client returns an http.Client using the transport, to be installed with
+// restclientgo.RestClient.WithHTTPClient.
+func (m *MockTransport) Client() *http.Client {
+	return &http.Client{Transport: m}
+}
+
+// Expect registers an expectation for requests with the given method and URL path.
+func (m *MockTransport) Expect(method, path string) *Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expectation := &Expectation{
+		method: method,
+		path:   path,
+		times:  -1,
+		respond: func(req *http.Request) (*http.Response, error) {
+			return newResponse(req, http.StatusOK, make(http.Header), nil), nil
+		},
+	}
+	m.expectations = append(m.expectations, expectation)
+
+	return expectation
+}
+
+// RoundTrip answers the request with the response of the first matching expectation,
+// or returns an ErrUnmatchedRequest error describing why no expectation matched.
+func (m *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	var report strings.Builder
+	for _, expectation := range m.expectations {
+		mismatches := expectation.match(req, body)
+		if len(mismatches) == 0 {
+			expectation.calls++
+			m.mu.Unlock()
+			return expectation.respond(req)
+		}
+
+		fmt.Fprintf(&report, "\n  expectation %s:", expectation)
+		for _, mismatch := range mismatches {
+			fmt.Fprintf(&report, "\n    - %s", strings.ReplaceAll(mismatch, "\n", "\n      "))
+		}
+	}
+
+	if len(m.expectations) == 0 {
+		report.WriteString("\n  no expectations registered")
+	}
+
+	description := fmt.Sprintf("%s %s%s", req.Method, req.URL.RequestURI(), report.String())
+	m.unmatched = append(m.unmatched, description)
+	m.mu.Unlock()
+
+	return nil, fmt.Errorf("%w: %s", ErrUnmatchedRequest, description)
+}
+
+// AssertExpectations reports unmatched requests and expectations whose call count
+// is not satisfied. It returns true if all the expectations are met.
+func (m *MockTransport) AssertExpectations(t TestingT) bool {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ok := true
+	for _, unmatched := range m.unmatched {
+		t.Errorf("%s: %s", ErrUnmatchedRequest, unmatched)
+		ok = false
+	}
+
+	for _, expectation := range m.expectations {
+		switch {
+		case expectation.times < 0 && expectation.calls == 0:
+			t.Errorf("expectation %s: not called", expectation)
+			ok = false
+		case expectation.times >= 0 && expectation.calls != expectation.times:
+			t.Errorf("expectation %s: called %d times, want %d", expectation, expectation.calls, expectation.times)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// Expectation describes the requests matched by a MockTransport and how to answer them.
+type Expectation struct {
+	method   string
+	path     string
+	matchers []RequestMatcher
+	times    int
+	calls    int
+	header   http.Header
+	respond  func(req *http.Request) (*http.Response, error)
+}
+
+// WithQuery requires the query parameter key to have value among its values.
+func (e *Expectation) WithQuery(key, value string) *Expectation {
+	return e.WithMatcher(func(req *http.Request, _ []byte) string {
+		values := req.URL.Query()[key]
+		if slices.Contains(values, value) {
+			return ""
+		}
+		return fmt.Sprintf("query %s: got %q, want %q", key, values, value)
+	})
+}
+
+// WithHeader requires the header key to have value among its values.
+func (e *Expectation) WithHeader(key, value string) *Expectation {
+	return e.WithMatcher(func(req *http.Request, _ []byte) string {
+		values := req.Header.Values(key)
+		if slices.Contains(values, value) {
+			return ""
+		}
+		return fmt.Sprintf("header %s: got %q, want %q", key, values, value)
+	})
+}
+
+// WithBody requires the request body to be equal to body.
+func (e *Expectation) WithBody(body string) *Expectation {
+	return e.WithMatcher(func(_ *http.Request, got []byte) string {
+		if string(got) == body {
+			return ""
+		}
+		return "body:\n" + diff(body, string(got))
+	})
+}
+
+// WithJSONBody requires the request body to be JSON equal to the encoding of v.
+func (e *Expectation) WithJSONBody(v any) *Expectation {
+	want, err := json.Marshal(v)
+	if err != nil {
+		return e.WithMatcher(func(*http.Request, []byte) string {
+			return fmt.Sprintf("json body: invalid expectation: %v", err)
+		})
+	}
+
+	return e.WithMatcher(func(_ *http.Request, got []byte) string {
+		var gotValue, wantValue any
+		if err := json.Unmarshal(got, &gotValue); err != nil {
+			return fmt.Sprintf("json body: %v", err)
+		}
+		_ = json.Unmarshal(want, &wantValue)
+
+		if reflect.DeepEqual(gotValue, wantValue) {
+			return ""
+		}
+
+		gotIndented, _ := json.MarshalIndent(gotValue, "", "  ")
+		wantIndented, _ := json.MarshalIndent(wantValue, "", "  ")
+		return "json body:\n" + diff(string(wantIndented), string(gotIndented))
+	})
+}
+
+// WithMatcher adds a custom request matcher.
+func (e *Expectation) WithMatcher(matcher RequestMatcher) *Expectation {
+	e.matchers = append(e.matchers, matcher)
+	return e
+}
+
+// Times requires the expectation to be matched exactly n times. Once matched n times
+// the expectation no longer matches. By default an expectation matches any number of
+// requests and must be matched at least once.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// Once requires the expectation to be matched exactly once.
+func (e *Expectation) Once() *Expectation {
+	return e.Times(1)
+}
+
+// WithResponseHeader adds a header to the canned response.
+func (e *Expectation) WithResponseHeader(key, value string) *Expectation {
+	if e.header == nil {
+		e.header = make(http.Header)
+	}
+	e.header.Add(key, value)
+	return e
+}
+
+// Respond answers the matched requests with statusCode and body.
+func (e *Expectation) Respond(statusCode int, body string) *Expectation {
+	return e.RespondFunc(func(req *http.Request) (*http.Response, error) {
+		return newResponse(req, statusCode, e.header.Clone(), []byte(body)), nil
+	})
+}
+
+// RespondJSON answers the matched requests with statusCode and the JSON encoding of v.
+func (e *Expectation) RespondJSON(statusCode int, v any) *Expectation {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return e.RespondError(err)
+	}
+
+	return e.RespondFunc(func(req *http.Request) (*http.Response, error) {
+		header := e.header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", "application/json")
+		}
+		return newResponse(req, statusCode, header, body), nil
+	})
+}
+
+// RespondError answers the matched requests with a transport error.
+func (e *Expectation) RespondError(err error) *Expectation {
+	return e.RespondFunc(func(*http.Request) (*http.Response, error) {
+		return nil, err
+	})
+}
+
+// RespondFunc answers the matched requests with the result of respond.
+func (e *Expectation) RespondFunc(respond func(req *http.Request) (*http.Response, error)) *Expectation {
+	e.respond = respond
+	return e
+}
+
+func (e *Expectation) String() string {
+	return e.method + " " + e.path
+}
+
+func (e *Expectation) match(req *http.Request, body []byte) []string {
+	var mismatches []string
+	if e.times >= 0 && e.calls >= e.times {
+		mismatches = append(mismatches, fmt.Sprintf("already called %d times", e.calls))
+	}
+
+	if req.Method != e.method {
+		mismatches = append(mismatches, fmt.Sprintf("method: got %q, want %q", req.Method, e.method))
+	}
+
+	if req.URL.Path != e.path {
+		mismatches = append(mismatches, fmt.Sprintf("path: got %q, want %q", req.URL.Path, e.path))
+	}
+
+	for _, matcher := range e.matchers {
+		if mismatch := matcher(req, body); mismatch != "" {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+
+	return mismatches
+}
+
+// readBody reads the request body, replacing it with an in-memory copy.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	defer req.Body.Close()
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+func newResponse(req *http.Request, statusCode int, header http.Header, body []byte) *http.Response {
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// diff returns a line diff between want and got, with removed lines prefixed by "-"
+// and added lines prefixed by "+".
+func diff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of wantLines[i:] and gotLines[j:].
+	lcs := make([][]int, len(wantLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(gotLines)+1)
+	}
+	for i := len(wantLines) - 1; i >= 0; i-- {
+		for j := len(gotLines) - 1; j >= 0; j-- {
+			if wantLines[i] == gotLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(wantLines) || j < len(gotLines) {
+		switch {
+		case i < len(wantLines) && j < len(gotLines) && wantLines[i] == gotLines[j]:
+			lines = append(lines, "  "+wantLines[i])
+			i++
+			j++
+		case i < len(wantLines) && (j == len(gotLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "- "+wantLines[i])
+			i++
+		default:
+			lines = append(lines, "+ "+gotLines[j])
+			j++
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,169 @@
+package restclientgotest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/henomis/restclientgo"
+)
+
+type createTodoRequest struct {
+	Title  string `json:"title"`
+	UserID int    `json:"userId"`
+}
+
+func (r *createTodoRequest) Path() (string, error) { return "/todos?notify=true", nil }
+func (r *createTodoRequest) Encode() (io.Reader, error) {
+	jsonBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(jsonBytes), nil
+}
+func (r *createTodoRequest) ContentType() string { return "application/json" }
+
+type TodoResponse struct {
+	HTTPStatusCode int    `json:"-"`
+	ID             int    `json:"id"`
+	Title          string `json:"title"`
+}
+
+func (r *TodoResponse) Decode(body io.Reader) error {
+	return json.NewDecoder(body).Decode(r)
+}
+func (r *TodoResponse) SetBody(body io.Reader) error {
+	_ = body
+	return nil
+}
+func (r *TodoResponse) AcceptContentType() string { return "application/json" }
+func (r *TodoResponse) SetStatusCode(code int) error {
+	r.HTTPStatusCode = code
+	return nil
+}
+func (r *TodoResponse) SetHeaders(headers restclientgo.Headers) error {
+	_ = headers
+	return nil
+}
+
+// recordingT records the failures reported by the transport.
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMockTransport(t *testing.T) {
+	mockTransport := NewMockTransport()
+	mockTransport.Expect(http.MethodPost, "/todos").
+		WithQuery("notify", "true").
+		WithHeader("Content-Type", "application/json").
+		WithJSONBody(map[string]any{"userId": 1, "title": "write tests"}).
+		Once().
+		RespondJSON(http.StatusCreated, map[string]any{"id": 7, "title": "write tests"})
+
+	restClient := restclientgo.New("https://example.com").WithHTTPClient(mockTransport.Client())
+
+	response := &TodoResponse{}
+	err := restClient.Post(context.Background(), &createTodoRequest{Title: "write tests", UserID: 1}, response)
+	if err != nil {
+		t.Fatalf("RestClient.Post() error = %v", err)
+	}
+
+	if response.HTTPStatusCode != http.StatusCreated || response.ID != 7 || response.Title != "write tests" {
+		t.Errorf("RestClient.Post() = %+v", response)
+	}
+
+	mockTransport.AssertExpectations(t)
+}
+
+func TestMockTransport_Unmatched(t *testing.T) {
+	mockTransport := NewMockTransport()
+	mockTransport.Expect(http.MethodPost, "/todos").
+		WithJSONBody(map[string]any{"userId": 1, "title": "write tests"}).
+		Once().
+		Respond(http.StatusCreated, `{"id":7}`)
+
+	restClient := restclientgo.New("https://example.com").WithHTTPClient(mockTransport.Client())
+
+	err := restClient.Post(context.Background(), &createTodoRequest{Title: "write docs", UserID: 1}, &TodoResponse{})
+	if !errors.Is(err, ErrUnmatchedRequest) {
+		t.Fatalf("RestClient.Post() error = %v, want %v", err, ErrUnmatchedRequest)
+	}
+
+	for _, want := range []string{`-   "title": "write tests",`, `+   "title": "write docs",`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("RestClient.Post() error = %v, want diff line %q", err, want)
+		}
+	}
+
+	recorder := &recordingT{}
+	if mockTransport.AssertExpectations(recorder) {
+		t.Errorf("MockTransport.AssertExpectations() = true, want false")
+	}
+
+	if len(recorder.errors) != 2 {
+		t.Errorf("MockTransport.AssertExpectations() reported %d errors, want 2: %v", len(recorder.errors), recorder.errors)
+	}
+}
+
+func TestMockTransport_RespondError(t *testing.T) {
+	errConnection := errors.New("connection refused")
+
+	mockTransport := NewMockTransport()
+	mockTransport.Expect(http.MethodPost, "/todos").RespondError(errConnection)
+
+	restClient := restclientgo.New("https://example.com").WithHTTPClient(mockTransport.Client())
+
+	err := restClient.Post(context.Background(), &createTodoRequest{}, &TodoResponse{})
+	if !errors.Is(err, errConnection) {
+		t.Errorf("RestClient.Post() error = %v, want %v", err, errConnection)
+	}
+
+	mockTransport.AssertExpectations(t)
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		got  string
+		diff string
+	}{
+		{
+			name: "equal",
+			want: "a\nb",
+			got:  "a\nb",
+			diff: "  a\n  b",
+		},
+		{
+			name: "changed line",
+			want: "a\nb\nc",
+			got:  "a\nx\nc",
+			diff: "  a\n- b\n+ x\n  c",
+		},
+		{
+			name: "added line",
+			want: "a",
+			got:  "a\nb",
+			diff: "  a\n+ b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diff(tt.want, tt.got); got != tt.diff {
+				t.Errorf("diff() = %q, want %q", got, tt.diff)
+			}
+		})
+	}
+}
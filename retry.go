@@ -0,0 +1,130 @@
+package restclientgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryBackoff = 100 * time.Millisecond
+	maxRetryDrainSize   = 64 * 1024
+)
+
+// RetryPolicy retries failed attempts of a call. The request is built and encoded again
+// for each attempt, so requests whose Encode cannot be repeated should not be retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled for each following retry.
+	// It defaults to 100ms.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between attempts, including Retry-After delays.
+	// 0 means no cap.
+	MaxBackoff time.Duration
+	// RetryOn reports whether an attempt should be retried. It defaults to retrying
+	// transport errors and 429, 502, 503 and 504 responses of idempotent requests: GET,
+	// HEAD, OPTIONS, TRACE, PUT and DELETE requests, and requests with an Idempotency-Key
+	// or X-Idempotency-Key header, as net/http does.
+	RetryOn func(*http.Response, error) bool
+}
+
+// isIdempotent reports whether httpRequest can be sent again by the default retry policy.
+func isIdempotent(httpRequest *http.Request) bool {
+	switch httpRequest.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	_, hasIdempotencyKey := httpRequest.Header["Idempotency-Key"]
+	_, hasXIdempotencyKey := httpRequest.Header["X-Idempotency-Key"]
+
+	return hasIdempotencyKey || hasXIdempotencyKey
+}
+
+func defaultRetryOn(httpResponse *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch httpResponse.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay returns the delay before the next attempt, and false if the attempt
+// should not be retried.
+func (p *RetryPolicy) retryDelay(
+	attempt int,
+	httpRequest *http.Request,
+	httpResponse *http.Response,
+	err error,
+) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if p.RetryOn != nil && !p.RetryOn(httpResponse, err) {
+		return 0, false
+	}
+	if p.RetryOn == nil && (!isIdempotent(httpRequest) || !defaultRetryOn(httpResponse, err)) {
+		return 0, false
+	}
+
+	delay := p.Backoff
+	if delay <= 0 {
+		delay = defaultRetryBackoff
+	}
+	for range attempt - 1 {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			break
+		}
+	}
+
+	if httpResponse != nil {
+		if retryAfter, ok := parseRetryAfter(httpResponse.Header.Get("Retry-After")); ok {
+			delay = retryAfter
+		}
+	}
+
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	return delay, true
+}
+
+// parseRetryAfter parses a Retry-After header, in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+package restclientgo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRestClient_BuildHTTPRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(req.Method + " " + req.URL.Path + " " + req.Header.Get("X-Signed") + " " + string(body)))
+	}))
+	defer server.Close()
+
+	restClient := New(server.URL + "/v1").WithRequestModifier(func(req *http.Request) *http.Request {
+		req.Header.Set("X-Signed", "yes")
+		return req
+	})
+
+	httpRequest, err := restClient.BuildHTTPRequest(context.Background(), http.MethodPut, &echoRequest{body: "payload"})
+	if err != nil {
+		t.Fatalf("RestClient.BuildHTTPRequest() error = %v", err)
+	}
+
+	if httpRequest.URL.String() != server.URL+"/v1/echo" || httpRequest.Header.Get("Content-Type") != "text/plain" {
+		t.Errorf("RestClient.BuildHTTPRequest() = %v %v", httpRequest.URL, httpRequest.Header)
+	}
+
+	// sent by another client
+	httpResponse, err := (&http.Client{}).Do(httpRequest)
+	if err != nil {
+		t.Fatalf("http.Client.Do() error = %v", err)
+	}
+
+	response := &EchoResponse{}
+	if err := restClient.HandleHTTPResponse(http.MethodPut, &echoRequest{body: "payload"}, httpResponse, response); err != nil {
+		t.Fatalf("RestClient.HandleHTTPResponse() error = %v", err)
+	}
+
+	want := "PUT /v1/echo yes payload"
+	if response.Body != want {
+		t.Errorf("RestClient.HandleHTTPResponse() = %q, want %q", response.Body, want)
+	}
+}
+
+func TestRestClient_HandleHTTPResponse(t *testing.T) {
+	type todo struct {
+		ID int `json:"id"`
+	}
+
+	newResponse := func(statusCode int, body string) *http.Response {
+		return &http.Response{
+			StatusCode: statusCode,
+			Status:     http.StatusText(statusCode),
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}
+	}
+
+	restClient := New("https://example.com")
+
+	// an offline replay
+	response := &JSONResponse[todo]{}
+	if err := restClient.HandleHTTPResponse(http.MethodGet, nil, newResponse(http.StatusOK, `{"id":7}`), response); err != nil {
+		t.Fatalf("RestClient.HandleHTTPResponse() error = %v", err)
+	}
+	if response.Data.ID != 7 || response.StatusCode != http.StatusOK {
+		t.Errorf("RestClient.HandleHTTPResponse() = %+v", response)
+	}
+
+	errorResponse := &JSONResponse[todo]{}
+	err := restClient.HandleHTTPResponse(http.MethodGet, nil, newResponse(http.StatusNotFound, `{"id":8}`), errorResponse,
+		WithExpectedStatus(http.StatusNotFound))
+	if err != nil || errorResponse.Data.ID != 8 {
+		t.Errorf("RestClient.HandleHTTPResponse() = %+v, error = %v", errorResponse, err)
+	}
+
+	err = restClient.HandleHTTPResponse(http.MethodGet, nil, newResponse(http.StatusOK, `{"id":9}`), &JSONResponse[todo]{},
+		WithMaxResponseSize(4))
+	if !errors.Is(err, ErrResponseSize) {
+		t.Errorf("RestClient.HandleHTTPResponse() error = %v, want %v", err, ErrResponseSize)
+	}
+
+	// the options of the request apply
+	request := &optionsRequest{options: []CallOption{WithExpectedStatus(http.StatusNotFound)}}
+	errorResponse = &JSONResponse[todo]{}
+	err = restClient.HandleHTTPResponse(http.MethodGet, request, newResponse(http.StatusNotFound, `{"id":10}`), errorResponse)
+	if err != nil || errorResponse.Data.ID != 10 {
+		t.Errorf("RestClient.HandleHTTPResponse() = %+v, error = %v", errorResponse, err)
+	}
+
+	// a replayed HEAD response is not decoded
+	headResponse := newResponse(http.StatusOK, "")
+	headResponse.ContentLength = 42
+	headResponse.Header.Set("Content-Type", "text/html")
+	if err := restClient.HandleHTTPResponse(http.MethodHead, nil, headResponse, &JSONResponse[todo]{}); err != nil {
+		t.Errorf("RestClient.HandleHTTPResponse() error = %v", err)
+	}
+}
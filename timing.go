@@ -0,0 +1,87 @@
+package restclientgo
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing is the timing of a request, passed to responses implementing TimingSetter.
+// Phases that did not happen, such as the DNS lookup of a reused connection, are 0.
+type Timing struct {
+	// Start is the time the request was sent.
+	Start time.Time
+	// DNSLookup is the duration of the DNS lookup.
+	DNSLookup time.Duration
+	// Connect is the duration of the TCP connection.
+	Connect time.Duration
+	// TLSHandshake is the duration of the TLS handshake.
+	TLSHandshake time.Duration
+	// TimeToFirstByte is the duration from Start to the first byte of the response.
+	TimeToFirstByte time.Duration
+	// Total is the duration from Start to the end of the response handling.
+	Total time.Duration
+	// ConnReused is true if the request used a connection of the pool.
+	ConnReused bool
+}
+
+// timingTrace collects the Timing of a request through an httptrace.ClientTrace.
+type timingTrace struct {
+	mu                sync.Mutex
+	value             Timing
+	dnsStart          time.Time
+	connectStart      time.Time
+	tlsHandshakeStart time.Time
+}
+
+func newTimingTrace() *timingTrace {
+	return &timingTrace{value: Timing{Start: time.Now()}}
+}
+
+func (t *timingTrace) withTrace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.record(func() { t.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.record(func() { t.value.DNSLookup = time.Since(t.dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			t.record(func() { t.connectStart = time.Now() })
+		},
+		ConnectDone: func(string, string, error) {
+			t.record(func() { t.value.Connect = time.Since(t.connectStart) })
+		},
+		TLSHandshakeStart: func() {
+			t.record(func() { t.tlsHandshakeStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.record(func() { t.value.TLSHandshake = time.Since(t.tlsHandshakeStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.record(func() { t.value.ConnReused = info.Reused })
+		},
+		GotFirstResponseByte: func() {
+			t.record(func() { t.value.TimeToFirstByte = time.Since(t.value.Start) })
+		},
+	})
+}
+
+func (t *timingTrace) record(update func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	update()
+}
+
+func (t *timingTrace) timing() Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	timing := t.value
+	timing.Total = time.Since(timing.Start)
+
+	return timing
+}
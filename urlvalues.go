@@ -3,6 +3,7 @@ package restclientgo
 import (
 	"fmt"
 	"net/url"
+	"strconv"
 )
 
 type URLValues url.Values
@@ -12,7 +13,8 @@ func NewURLValues() *URLValues {
 	return &URLValues{}
 }
 
-// Add adds a string value to the URLValues.
+// Add adds a string value to the URLValues. Empty strings are omitted, use the
+// package level Add to choose a ZeroPolicy.
 func (p *URLValues) Add(key string, value *string) {
 	if value != nil && *value != "" {
 		(*url.Values)(p).Add(key, *value)
@@ -44,13 +46,18 @@ func (p *URLValues) AddBoolAsInt(key string, value *bool) {
 	}
 }
 
-// AddFloat adds a float value to the URLValues.
+// AddFloat adds a float value to the URLValues, formatted without losing precision.
 func (p *URLValues) AddFloat(key string, value *float64) {
 	if value != nil {
-		(*url.Values)(p).Add(key, fmt.Sprintf("%f", *value))
+		(*url.Values)(p).Add(key, strconv.FormatFloat(*value, 'f', -1, 64))
 	}
 }
 
+// AddValue adds a value to the URLValues as it is, even if empty.
+func (p *URLValues) AddValue(key string, value string) {
+	(*url.Values)(p).Add(key, value)
+}
+
 // Del deletes the URLValues associated with key.
 func (p *URLValues) Del(key string) {
 	(*url.Values)(p).Del(key)